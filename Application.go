@@ -1,19 +1,21 @@
 package aero
 
 import (
-	"compress/gzip"
 	stdContext "context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/aerogo/csp"
@@ -29,12 +31,15 @@ type Application struct {
 	Security              ApplicationSecurity
 	ContentSecurityPolicy *csp.ContentSecurityPolicy
 
-	router         Router
+	host           Host
+	hosts          []*Host
+	mounted        []*Application
 	rewrite        []func(RewriteContext)
 	middleware     []Middleware
+	encoders       []encoder
+	compressors    []*compressor
 	pushConditions []func(Context) bool
 	contextPool    sync.Pool
-	gzipWriterPool sync.Pool
 	pushOptions    http.PushOptions
 	serversMutex   sync.Mutex
 	servers        [2]*http.Server
@@ -54,6 +59,13 @@ func New() *Application {
 		stop:                  make(chan os.Signal, 1),
 	}
 
+	// Default host
+	app.host.Group = &Group{app: app, host: &app.host}
+	app.host.router.Store(&Router{})
+	app.host.notFound = emptyResponse
+	app.host.methodNotAllowed = emptyResponse
+	app.host.options = emptyResponse
+
 	// Default CSP
 	app.ContentSecurityPolicy.SetMap(csp.Map{
 		"default-src":  "'none'",
@@ -70,6 +82,18 @@ func New() *Application {
 		"form-action":  "'self'",
 	})
 
+	// Encoders for content negotiation
+	app.RegisterEncoder(contentTypeJSON, encodeJSON)
+	app.RegisterEncoder(contentTypeXML, encodeXML)
+	app.RegisterEncoder(contentTypeCSV, encodeCSV)
+	app.RegisterEncoder(contentTypeMessagePack, encodeMessagePack)
+	app.RegisterEncoder(contentTypeCBOR, encodeCBOR)
+
+	// Compressors for response bodies, later ones are preferred
+	app.RegisterCompressor(contentEncodingDeflate, compressDeflate)
+	app.RegisterCompressor(contentEncodingGzip, compressGzip)
+	app.RegisterCompressor(contentEncodingBrotli, compressBrotli)
+
 	// MIME types
 	_ = mime.AddExtensionType(".apng", "image/apng")
 
@@ -103,36 +127,120 @@ func New() *Application {
 }
 
 // Get registers your function to be called when the given GET path has been requested.
-func (app *Application) Get(path string, handler Handler) {
-	app.router.Add(http.MethodGet, path, handler)
+func (app *Application) Get(path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(http.MethodGet, path, handler, middleware)
 }
 
 // Post registers your function to be called when the given POST path has been requested.
-func (app *Application) Post(path string, handler Handler) {
-	app.router.Add(http.MethodPost, path, handler)
+func (app *Application) Post(path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(http.MethodPost, path, handler, middleware)
 }
 
 // Delete registers your function to be called when the given DELETE path has been requested.
-func (app *Application) Delete(path string, handler Handler) {
-	app.router.Add(http.MethodDelete, path, handler)
+func (app *Application) Delete(path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(http.MethodDelete, path, handler, middleware)
 }
 
 // Put registers your function to be called when the given PUT path has been requested.
-func (app *Application) Put(path string, handler Handler) {
-	app.router.Add(http.MethodPut, path, handler)
+func (app *Application) Put(path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(http.MethodPut, path, handler, middleware)
+}
+
+// Patch registers your function to be called when the given PATCH path has been requested.
+func (app *Application) Patch(path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(http.MethodPatch, path, handler, middleware)
+}
+
+// Head registers your function to be called when the given HEAD path has been requested.
+// This overrides the automatic HEAD response derived from the GET route.
+func (app *Application) Head(path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(http.MethodHead, path, handler, middleware)
+}
+
+// Options registers your function to be called when the given OPTIONS path has been requested.
+// This overrides the automatic OPTIONS response.
+func (app *Application) Options(path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(http.MethodOptions, path, handler, middleware)
+}
+
+// Handle registers your function to be called when the given path has been requested
+// with the given method. Non-standard methods like PROPFIND are supported as well.
+func (app *Application) Handle(method string, path string, handler Handler, middleware ...Middleware) *Route {
+	return app.Router().add(method, path, handler, middleware)
 }
 
 // Any registers your function to be called with any http method.
-func (app *Application) Any(path string, handler Handler) {
-	app.Get(path, handler)
-	app.Post(path, handler)
-	app.Delete(path, handler)
-	app.Put(path, handler)
+// Non-standard methods like PROPFIND are included unless they have a route of their own.
+func (app *Application) Any(path string, handler Handler, middleware ...Middleware) {
+	for _, method := range append(methods[:], anyMethod) {
+		app.Router().add(method, path, handler, middleware)
+	}
+}
+
+// Mount registers an http.Handler, e.g. another application,
+// for all requests whose path starts with the given prefix.
+// The prefix is stripped from the request path before the
+// handler is called and the middleware of the application
+// is applied to the mounted handler.
+func (app *Application) Mount(prefix string, handler http.Handler) {
+	app.host.Mount(prefix, handler)
+}
+
+// Static serves the files in the directory below the prefix.
+func (app *Application) Static(prefix string, dir string, options ...StaticOptions) *Route {
+	return app.host.Static(prefix, dir, options...)
+}
+
+// StaticFS serves the files of the file system below the prefix, e.g. an embed.FS.
+func (app *Application) StaticFS(prefix string, files fs.FS, options ...StaticOptions) *Route {
+	return app.host.StaticFS(prefix, files, options...)
+}
+
+// NotFound registers the handler that is called when no route matches the request path.
+// The response status is preset to 404 and the handler runs through the middleware chain.
+func (app *Application) NotFound(handler Handler) {
+	app.host.notFound = handler
+}
+
+// MethodNotAllowed registers the handler that is called when the request path
+// is only registered for other methods. The response status is preset to 405,
+// the Allow header is already set and the handler runs through the middleware chain.
+func (app *Application) MethodNotAllowed(handler Handler) {
+	app.host.methodNotAllowed = handler
+}
+
+// URL returns the path of the route with the given name and parameters.
+// Parameters are given as name and value pairs, e.g. "nick", "alice".
+func (app *Application) URL(name string, params ...string) (string, error) {
+	return app.Router().URL(name, params...)
 }
 
-// Router returns the router used by the application.
+// PrintRoutes writes a table of all registered routes to the given writer.
+func (app *Application) PrintRoutes(writer io.Writer) {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "METHOD\tPATH\tNAME\tMIDDLEWARE\tTAGS")
+
+	for _, route := range app.Router().Routes() {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\n", route.Method, route.Path, route.Name, route.Middleware, strings.Join(route.Tags, ", "))
+	}
+
+	table.Flush()
+}
+
+// Router returns the router that currently serves the requests of the application.
+// Routes must not be added to or removed from a router that is serving requests.
+// To modify the routes at runtime, build a new router, e.g. with Clone,
+// and replace the current one via SetRouter.
 func (app *Application) Router() *Router {
-	return &app.router
+	return app.host.Router()
+}
+
+// SetRouter binds the application middleware to the router and atomically
+// replaces the router of the application. Requests that are in flight finish
+// with the previous router, new requests use the given router.
+// The router must not be modified after it has been set.
+func (app *Application) SetRouter(router *Router) {
+	app.host.SetRouter(router)
 }
 
 // Run starts your application.
@@ -234,7 +342,9 @@ func (app *Application) newContext(req *http.Request, res http.ResponseWriter) *
 	ctx.response.inner = res
 	ctx.session = nil
 	ctx.paramCount = 0
+	ctx.extraParams = ctx.extraParams[:0]
 	ctx.modifierCount = 0
+	ctx.extraModifiers = ctx.extraModifiers[:0]
 	return ctx
 }
 
@@ -246,12 +356,20 @@ func (app *Application) ServeHTTP(response http.ResponseWriter, request *http.Re
 		rewrite(ctx)
 	}
 
-	app.router.Lookup(request.Method, request.URL.Path, ctx)
+	host := app.selectHost(request.Host, ctx)
+	router := host.Router()
+	app.lookup(router, request, ctx)
 
 	if ctx.handler == nil {
-		response.WriteHeader(http.StatusNotFound)
-		ctx.Close()
-		return
+		path := request.URL.Path
+
+		if app.Config.Routing.Paths != PathsStrict {
+			path = cleanPath(path)
+		}
+
+		app.unrouted(host, router, request.Method, path, ctx)
+	} else {
+		app.limitBody(request, ctx)
 	}
 
 	err := ctx.handler(ctx)
@@ -265,28 +383,177 @@ func (app *Application) ServeHTTP(response http.ResponseWriter, request *http.Re
 	ctx.Close()
 }
 
-// acquireGZipWriter will return a clean gzip writer from the pool.
-func (app *Application) acquireGZipWriter(response io.Writer) *gzip.Writer {
-	var writer *gzip.Writer
-	obj := app.gzipWriterPool.Get()
+// selectHost returns the virtual host matching the requested host name
+// and adds the host parameters to the context. Hosts without parameters
+// or wildcards take precedence. If no virtual host matches, the default
+// host of the application is returned.
+func (app *Application) selectHost(requestHost string, ctx *context) *Host {
+	if len(app.hosts) == 0 {
+		return &app.host
+	}
+
+	name := hostName(requestHost)
+
+	for _, host := range app.hosts {
+		if host.pattern == name {
+			return host
+		}
+	}
+
+	for _, host := range app.hosts {
+		if host.match(name, ctx) {
+			return host
+		}
+	}
+
+	return &app.host
+}
+
+// lookup finds the route for the request according to the configured
+// path policy. Non-canonical paths are either served, redirected
+// or rejected. HEAD requests fall back to the GET route.
+func (app *Application) lookup(router *Router, request *http.Request, ctx *context) {
+	path := request.URL.Path
+	policy := app.Config.Routing.Paths
+	params := ctx.paramCount
+	candidates := [4]string{path}
+	count := 1
+
+	if policy != PathsStrict {
+		candidates[0] = cleanPath(path)
+		candidates[1] = toggleTrailingSlash(candidates[0])
+		count = 2
+	}
+
+	half := count
+
+	if app.Config.Routing.CaseInsensitive {
+		for i := 0; i < half; i++ {
+			candidates[half+i] = toLowerASCII(candidates[i])
+		}
+
+		count *= 2
+	}
+
+	for i := 0; i < count; i++ {
+		candidate := candidates[i]
+		lowered := i >= half
+
+		if lowered && candidate == candidates[i-half] {
+			continue
+		}
+
+		find(router, request.Method, candidate, ctx)
+
+		if ctx.route == nil {
+			continue
+		}
+
+		if request.Method == http.MethodHead && ctx.route.method == http.MethodGet {
+			ctx.response.inner = headResponse{ctx.response.inner}
+		}
+
+		if lowered {
+			candidate = keepParameterCase(candidate, candidates[i-half], ctx.route.path, params, ctx)
+		}
+
+		canonical := canonicalTrailingSlash(candidate, ctx.route.path)
+
+		switch {
+		case canonical == path:
+			return
+
+		case policy == PathsStrict && canonical != candidate:
+			ctx.paramCount = params
+			ctx.route = nil
+			ctx.handler = nil
+
+		case lowered || policy == PathsRedirect:
+			redirect(canonical, request, ctx)
+		}
+
+		return
+	}
+}
+
+// unrouted assigns the handler for requests that have no
+// route registered for their method. OPTIONS requests are
+// answered with the list of allowed methods. If the path exists
+// for other methods, the response is a 405 with the Allow header,
+// otherwise a 404.
+func (app *Application) unrouted(host *Host, router *Router, method string, path string, ctx *context) {
+	allowed := allowedMethods(router, path, app.Config.Routing.Paths == PathsStrict)
+	handlers := host.errorHandlers()
+
+	if len(allowed) == 0 {
+		ctx.status = http.StatusNotFound
+		ctx.handler = handlers.notFound
+		return
+	}
+
+	ctx.response.SetHeader(allowHeader, strings.Join(allowed, ", "))
+
+	if method == http.MethodOptions {
+		ctx.status = http.StatusNoContent
+		ctx.handler = handlers.options
+		return
+	}
+
+	ctx.status = http.StatusMethodNotAllowed
+	ctx.handler = handlers.methodNotAllowed
+}
+
+// allowedMethods returns the methods the router responds to for the given path,
+// including the automatically handled HEAD and OPTIONS methods. In strict mode,
+// routes only count if their trailing slash matches the path.
+func allowedMethods(router *Router, path string, strict bool) []string {
+	registered := router.allowed(path, strict)
+
+	if len(registered) == 0 {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(registered)+2)
+	hasHead := false
+	hasOptions := false
 
-	if obj == nil {
-		writer, _ = gzip.NewWriterLevel(response, gzip.BestCompression)
-		return writer
+	for _, method := range registered {
+		switch method {
+		case http.MethodHead:
+			hasHead = true
+		case http.MethodOptions:
+			hasOptions = true
+		}
+	}
+
+	for _, method := range registered {
+		allowed = append(allowed, method)
+
+		if method == http.MethodGet && !hasHead {
+			allowed = append(allowed, http.MethodHead)
+		}
+	}
+
+	if !hasOptions {
+		allowed = append(allowed, http.MethodOptions)
 	}
 
-	writer = obj.(*gzip.Writer)
-	writer.Reset(response)
-	return writer
+	return allowed
 }
 
 // BindMiddleware applies the middleware to every router node.
 // This is called by `Run` automatically and should never be called
 // outside of tests.
 func (app *Application) BindMiddleware() {
-	app.router.bind(func(handler Handler) Handler {
-		return handler.Bind(app.middleware...)
-	})
+	for _, host := range app.hosts {
+		host.bind(app.middleware)
+	}
+
+	app.host.bind(app.middleware)
+
+	for _, mounted := range app.mounted {
+		mounted.BindMiddleware()
+	}
 }
 
 // createServer creates an http server instance.
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -33,6 +35,11 @@ func TestApplicationAny(t *testing.T) {
 		"POST",
 		"PUT",
 		"DELETE",
+		"PATCH",
+		"CONNECT",
+		"OPTIONS",
+		"TRACE",
+		"PROPFIND",
 	}
 
 	for _, method := range methods {
@@ -54,6 +61,40 @@ func TestApplicationAny(t *testing.T) {
 	}
 }
 
+func TestApplicationMethods(t *testing.T) {
+	app := aero.New()
+
+	handler := func(ctx aero.Context) error {
+		return ctx.Text(ctx.Request().Method())
+	}
+
+	app.Patch("/", handler)
+	app.Head("/", handler)
+	app.Options("/", handler)
+	app.Handle("PROPFIND", "/", handler)
+	app.Handle("MKCOL", "/collection/:name", handler)
+
+	for _, method := range []string{"PATCH", "OPTIONS", "PROPFIND"} {
+		request := httptest.NewRequest(method, "/", nil)
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), method)
+	}
+
+	request := httptest.NewRequest("MKCOL", "/collection/docs", nil)
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusOK)
+
+	request = httptest.NewRequest("COPY", "/", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, response.Header().Get("Allow"), "HEAD, PATCH, OPTIONS, PROPFIND")
+}
+
 func TestApplicationRewrite(t *testing.T) {
 	app := aero.New()
 
@@ -179,3 +220,408 @@ func TestApplicationOnError(t *testing.T) {
 
 	test(app, "/")
 }
+
+func TestApplicationNotFound(t *testing.T) {
+	app := aero.New()
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	app.NotFound(func(ctx aero.Context) error {
+		return ctx.HTML("<h1>" + http.StatusText(ctx.Status()) + "</h1>")
+	})
+
+	app.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			ctx.Response().SetHeader("X-Middleware", "true")
+			return next(ctx)
+		}
+	})
+
+	app.BindMiddleware()
+	response := test(app, "/404")
+
+	assert.Equal(t, response.Code, http.StatusNotFound)
+	assert.Equal(t, response.Body.String(), "<h1>Not Found</h1>")
+	assert.Equal(t, response.Header().Get("X-Middleware"), "true")
+}
+
+func TestApplicationMethodNotAllowed(t *testing.T) {
+	app := aero.New()
+	handler := func(ctx aero.Context) error { return ctx.Text(helloWorld) }
+
+	app.Get("/resource/:id", handler)
+	app.Put("/resource/:id", handler)
+	app.Post("/resource", handler)
+
+	// Default response
+	request := httptest.NewRequest(http.MethodDelete, "/resource/1", nil)
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, response.Header().Get("Allow"), "GET, HEAD, PUT, OPTIONS")
+	assert.Equal(t, response.Body.String(), "")
+
+	// Custom response
+	app.MethodNotAllowed(func(ctx aero.Context) error {
+		return ctx.Text(ctx.Response().Header("Allow"))
+	})
+
+	request = httptest.NewRequest(http.MethodGet, "/resource", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, response.Body.String(), "POST, OPTIONS")
+}
+
+func TestApplicationHead(t *testing.T) {
+	app := aero.New()
+	text := strings.Repeat(helloWorld, 100)
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(text)
+	})
+
+	app.Get("/small/:id", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("id"))
+	})
+
+	request := httptest.NewRequest(http.MethodHead, "/", nil)
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "")
+	assert.Equal(t, response.Header().Get("Content-Length"), strconv.Itoa(len(text)))
+	assert.Equal(t, response.Header().Get("ETag"), strconv.Quote(aero.ETagString(text)))
+
+	request = httptest.NewRequest(http.MethodHead, "/small/42", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "")
+	assert.Equal(t, response.Header().Get("Content-Length"), "2")
+
+	request = httptest.NewRequest(http.MethodHead, "/404", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
+
+func TestApplicationOptions(t *testing.T) {
+	app := aero.New()
+	handler := func(ctx aero.Context) error { return ctx.Text(helloWorld) }
+
+	app.Get("/resource", handler)
+	app.Post("/resource", handler)
+	app.Get("/custom", handler)
+
+	app.Router().Add(http.MethodOptions, "/custom", func(ctx aero.Context) error {
+		ctx.Response().SetHeader("Allow", "GET")
+		return ctx.Text("custom")
+	})
+
+	app.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			ctx.Response().SetHeader("Access-Control-Allow-Origin", "*")
+			return next(ctx)
+		}
+	})
+
+	app.BindMiddleware()
+
+	// Automatic response
+	request := httptest.NewRequest(http.MethodOptions, "/resource", nil)
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusNoContent)
+	assert.Equal(t, response.Header().Get("Allow"), "GET, HEAD, POST, OPTIONS")
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Origin"), "*")
+	assert.Equal(t, response.Body.String(), "")
+
+	// Custom response
+	request = httptest.NewRequest(http.MethodOptions, "/custom", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Allow"), "GET")
+	assert.Equal(t, response.Body.String(), "custom")
+
+	// Unknown path
+	request = httptest.NewRequest(http.MethodOptions, "/404", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
+
+func TestApplicationPrintRoutes(t *testing.T) {
+	app := aero.New()
+	handler := func(ctx aero.Context) error { return nil }
+	middleware := func(next aero.Handler) aero.Handler { return next }
+
+	app.Get("/", handler).Name("home")
+	app.Group("/admin", middleware, middleware).Post("/users", handler)
+
+	buffer := strings.Builder{}
+	app.PrintRoutes(&buffer)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+	assert.Equal(t, len(lines), 3)
+	assert.Equal(t, strings.Fields(lines[0])[0], "METHOD")
+	assert.DeepEqual(t, strings.Fields(lines[1]), []string{"GET", "/", "home", "0"})
+	assert.DeepEqual(t, strings.Fields(lines[2]), []string{"POST", "/admin/users", "2"})
+}
+
+func TestApplicationMount(t *testing.T) {
+	app := aero.New()
+	blog := aero.New()
+
+	blog.Get("/", func(ctx aero.Context) error {
+		return ctx.Text("blog")
+	})
+
+	blog.Get("/post/:id", func(ctx aero.Context) error {
+		return ctx.Text("post " + ctx.Get("id"))
+	})
+
+	blog.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			ctx.Response().SetHeader("X-Blog", "true")
+			return next(ctx)
+		}
+	})
+
+	app.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			ctx.Response().SetHeader("X-App", "true")
+			return next(ctx)
+		}
+	})
+
+	app.Mount("/blog", blog)
+	app.Mount("/debug", http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		_, _ = response.Write([]byte(request.Method + " " + request.URL.Path))
+	}))
+
+	app.BindMiddleware()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{"GET", "/blog", "blog"},
+		{"GET", "/blog/", "blog"},
+		{"GET", "/blog/post/42", "post 42"},
+		{"GET", "/debug", "GET /"},
+		{"POST", "/debug/pprof/heap", "POST /pprof/heap"},
+		{"PROPFIND", "/debug/files", "PROPFIND /files"},
+		{"PROPFIND", "/debug", "PROPFIND /"},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(test.method, test.path, nil)
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), test.body)
+		assert.Equal(t, response.Header().Get("X-App"), "true")
+	}
+
+	response := test(app, "/blog/post/42")
+	assert.Equal(t, response.Header().Get("X-Blog"), "true")
+
+	response = test(app, "/blog/404")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+
+	// Custom methods registered elsewhere don't hide the mounted handler
+	app.Handle("PROPFIND", "/files", func(ctx aero.Context) error {
+		return ctx.Text("files")
+	})
+
+	request := httptest.NewRequest("PROPFIND", "/debug/files", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Body.String(), "PROPFIND /files")
+
+	request = httptest.NewRequest("PROPFIND", "/other", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
+
+func TestApplicationPaths(t *testing.T) {
+	app := aero.New()
+
+	app.Get("/blog", func(ctx aero.Context) error {
+		return ctx.Text("blog")
+	})
+
+	app.Get("/docs/", func(ctx aero.Context) error {
+		return ctx.Text("docs")
+	})
+
+	app.Post("/blog", func(ctx aero.Context) error {
+		return ctx.Text("post")
+	})
+
+	tests := []struct {
+		policy   string
+		method   string
+		path     string
+		code     int
+		location string
+	}{
+		{aero.PathsLenient, "GET", "/blog", http.StatusOK, ""},
+		{aero.PathsLenient, "GET", "/blog/", http.StatusOK, ""},
+		{aero.PathsLenient, "GET", "//blog", http.StatusOK, ""},
+		{aero.PathsLenient, "GET", "/docs/../blog", http.StatusOK, ""},
+		{aero.PathsLenient, "GET", "/docs", http.StatusOK, ""},
+		{aero.PathsStrict, "GET", "/blog", http.StatusOK, ""},
+		{aero.PathsStrict, "GET", "/blog/", http.StatusNotFound, ""},
+		{aero.PathsStrict, "GET", "/./blog", http.StatusNotFound, ""},
+		{aero.PathsStrict, "GET", "/docs", http.StatusNotFound, ""},
+		{aero.PathsRedirect, "GET", "/blog", http.StatusOK, ""},
+		{aero.PathsRedirect, "GET", "/blog/", http.StatusMovedPermanently, "/blog"},
+		{aero.PathsRedirect, "GET", "/blog/?page=2", http.StatusMovedPermanently, "/blog?page=2"},
+		{aero.PathsRedirect, "GET", "//docs/./", http.StatusMovedPermanently, "/docs/"},
+		{aero.PathsRedirect, "GET", "/docs", http.StatusMovedPermanently, "/docs/"},
+		{aero.PathsRedirect, "HEAD", "/blog/", http.StatusMovedPermanently, "/blog"},
+		{aero.PathsRedirect, "POST", "/blog/", http.StatusPermanentRedirect, "/blog"},
+	}
+
+	for _, test := range tests {
+		app.Config.Routing.Paths = test.policy
+		request := httptest.NewRequest(test.method, test.path, nil)
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+
+		assert.Equal(t, response.Code, test.code)
+		assert.Equal(t, response.Header().Get("Location"), test.location)
+	}
+}
+
+func TestApplicationPathsCaseInsensitive(t *testing.T) {
+	app := aero.New()
+	app.Config.Routing.CaseInsensitive = true
+
+	app.Get("/blog/:id", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("id"))
+	})
+
+	response := test(app, "/blog/About")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "About")
+
+	response = test(app, "/Blog/About?x=1")
+	assert.Equal(t, response.Code, http.StatusMovedPermanently)
+	assert.Equal(t, response.Header().Get("Location"), "/blog/About?x=1")
+
+	app.Get("/Files/*path", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("path"))
+	})
+
+	app.Get("/short/:code.:ext", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("code"))
+	})
+
+	response = test(app, "/BLOG/aB3xZ/")
+	assert.Equal(t, response.Code, http.StatusMovedPermanently)
+	assert.Equal(t, response.Header().Get("Location"), "/blog/aB3xZ")
+
+	response = test(app, "/SHORT/Xy7Q.PNG")
+	assert.Equal(t, response.Code, http.StatusMovedPermanently)
+	assert.Equal(t, response.Header().Get("Location"), "/short/Xy7Q.PNG")
+
+	app.Config.Routing.Paths = aero.PathsStrict
+	response = test(app, "/Blog/about/")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
+
+func TestApplicationPathsStrictParameters(t *testing.T) {
+	app := aero.New()
+	app.Config.Routing.Paths = aero.PathsStrict
+
+	app.Get("/user/:id", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("id"))
+	})
+
+	app.Get("/blog/", func(ctx aero.Context) error {
+		return ctx.Text("blog")
+	})
+
+	response := test(app, "/user/1")
+	assert.Equal(t, response.Code, http.StatusOK)
+
+	response = test(app, "/user/1/")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+	assert.Equal(t, response.Header().Get("Allow"), "")
+
+	response = test(app, "/blog")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+
+	request := httptest.NewRequest(http.MethodPost, "/user/1", nil)
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	assert.Equal(t, recorder.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, recorder.Header().Get("Allow"), "GET, HEAD, OPTIONS")
+}
+
+func TestApplicationSetRouter(t *testing.T) {
+	app := aero.New()
+
+	app.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			ctx.Response().SetHeader("X-Middleware", "true")
+			return next(ctx)
+		}
+	})
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	app.BindMiddleware()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 100; i++ {
+			router := app.Router().Clone()
+
+			router.Add("GET", "/landing/"+strconv.Itoa(i), func(ctx aero.Context) error {
+				return ctx.Text("landing")
+			})
+
+			app.SetRouter(router)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		response := test(app, "/")
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), helloWorld)
+	}
+
+	<-done
+	response := test(app, "/landing/99")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "landing")
+	assert.Equal(t, response.Header().Get("X-Middleware"), "true")
+
+	router := app.Router().Clone()
+	assert.True(t, router.Remove("GET", "/landing/99"))
+	app.SetRouter(router)
+	response = test(app, "/landing/99")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
@@ -0,0 +1,332 @@
+package aero
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// multipartMemory is the number of bytes of a multipart form
+// that are kept in memory when binding, the rest is stored on disk.
+const multipartMemory = 32 << 20
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType     = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// Bind decodes the request into the struct that dst points to and validates it.
+// The URL query is bound to fields with a `query` tag, the body is decoded
+// according to its Content-Type: JSON via the `json` tags, URL encoded and
+// multipart forms via the `form` tags. Route parameters are bound to fields
+// with a `param` tag and take precedence over the other sources.
+// If a value can't be converted or violates the rules of the `validate` tags,
+// the returned error is a *ValidationError.
+func (ctx *context) Bind(dst interface{}) error {
+	value := reflect.ValueOf(dst)
+
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+
+	value = value.Elem()
+	fields := fieldsOf(value.Type())
+	invalid := &ValidationError{}
+	request := ctx.request.inner
+
+	err := bindValues(value, fields, request.URL.Query(), queryTag, invalid)
+
+	if err != nil {
+		return err
+	}
+
+	err = ctx.bindBody(dst, value, fields, invalid)
+
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if field.param == "" {
+			continue
+		}
+
+		param := ctx.Get(field.param)
+
+		if param == "" {
+			continue
+		}
+
+		err = bindField(value, field, []string{param}, invalid)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	validateStruct(value, "", invalid)
+
+	if len(invalid.Fields) > 0 {
+		return invalid
+	}
+
+	return nil
+}
+
+// bindBody decodes the request body according to its Content-Type.
+func (ctx *context) bindBody(dst interface{}, value reflect.Value, fields []field, invalid *ValidationError) error {
+	request := ctx.request.inner
+	contentType := request.Header.Get(contentTypeHeader)
+
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		err = json.NewDecoder(request.Body).Decode(dst)
+		typeError := &json.UnmarshalTypeError{}
+
+		switch {
+		case err == io.EOF:
+			return nil
+
+		case errors.As(err, &typeError):
+			name := typeError.Field
+
+			if name == "" {
+				name = "body"
+			}
+
+			invalid.add(name, "type", "", "must be of type "+typeError.Type.String())
+			return nil
+		}
+
+		return err
+
+	case mediaType == "application/x-www-form-urlencoded":
+		err = request.ParseForm()
+
+		if err != nil {
+			return err
+		}
+
+		return bindValues(value, fields, request.PostForm, formTag, invalid)
+
+	case mediaType == "multipart/form-data":
+		err = request.ParseMultipartForm(multipartMemory)
+
+		if err != nil {
+			return err
+		}
+
+		err = bindValues(value, fields, request.MultipartForm.Value, formTag, invalid)
+
+		if err != nil {
+			return err
+		}
+
+		bindFiles(value, fields, request.MultipartForm.File)
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+	}
+}
+
+// Tags used for binding values.
+const (
+	formTag  = "form"
+	queryTag = "query"
+)
+
+// bindValues assigns the values to the fields with the given tag.
+func bindValues(value reflect.Value, fields []field, values url.Values, tag string, invalid *ValidationError) error {
+	for _, field := range fields {
+		name := field.query
+
+		if tag == formTag {
+			name = field.form
+		}
+
+		if name == "" {
+			continue
+		}
+
+		list, found := values[name]
+
+		if !found || len(list) == 0 {
+			continue
+		}
+
+		err := bindField(value, field, list, invalid)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindField converts the values and assigns them to the field.
+// Values that can't be converted mark the field as invalid.
+func bindField(value reflect.Value, field field, values []string, invalid *ValidationError) error {
+	fieldValue := value.FieldByIndex(field.index)
+
+	if fieldValue.Type() == fileHeaderType || fieldValue.Type() == fileHeadersType {
+		return nil
+	}
+
+	err := setField(fieldValue, values)
+
+	if errors.Is(err, errUnsupportedType) {
+		return fmt.Errorf("Field %s: %w", field.name, err)
+	}
+
+	if err != nil && !invalid.has(field.name) {
+		invalid.add(field.name, "type", "", "must be of type "+fieldValue.Type().String())
+	}
+
+	return nil
+}
+
+// bindFiles assigns the uploaded files to fields of type
+// *multipart.FileHeader and []*multipart.FileHeader.
+func bindFiles(value reflect.Value, fields []field, files map[string][]*multipart.FileHeader) {
+	for _, field := range fields {
+		headers := files[field.form]
+
+		if field.form == "" || len(headers) == 0 {
+			continue
+		}
+
+		fieldValue := value.FieldByIndex(field.index)
+
+		switch fieldValue.Type() {
+		case fileHeaderType:
+			fieldValue.Set(reflect.ValueOf(headers[0]))
+		case fileHeadersType:
+			fieldValue.Set(reflect.ValueOf(headers))
+		}
+	}
+}
+
+// errUnsupportedType is returned when a field type can't be bound.
+var errUnsupportedType = errors.New("Unsupported type")
+
+// setField assigns the values to the field. Slices receive
+// all values, other types only the first one.
+func setField(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && !reflect.PtrTo(field.Type()).Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+
+		for index, value := range values {
+			err := setValue(slice.Index(index), value)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+		return nil
+	}
+
+	return setValue(field, values[0])
+}
+
+// setValue converts the string and assigns it to the field.
+func setValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		pointer := reflect.New(field.Type().Elem())
+		err := setValue(pointer.Elem(), value)
+
+		if err != nil {
+			return err
+		}
+
+		field.Set(pointer)
+		return nil
+	}
+
+	unmarshaler, isUnmarshaler := field.Addr().Interface().(encoding.TextUnmarshaler)
+
+	if isUnmarshaler {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(parsed)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == durationType {
+			parsed, err := time.ParseDuration(value)
+
+			if err != nil {
+				return err
+			}
+
+			field.SetInt(int64(parsed))
+			return nil
+		}
+
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(parsed)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(parsed)
+
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(parsed)
+
+	default:
+		return fmt.Errorf("%w %s", errUnsupportedType, field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package aero_test
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+type signup struct {
+	ID       int           `param:"id"`
+	Name     string        `json:"name" form:"name" validate:"required,min=3,max=20"`
+	Email    string        `json:"email" form:"email" validate:"required,email"`
+	Plan     string        `json:"plan" form:"plan" validate:"oneof=free pro"`
+	Age      *int          `json:"age" form:"age" validate:"min=18"`
+	Tags     []string      `json:"tags" form:"tag" query:"tag" validate:"max=2"`
+	Page     int           `query:"page"`
+	Timeout  time.Duration `query:"timeout"`
+	Verbose  bool          `query:"verbose"`
+	internal string
+}
+
+func bind(t *testing.T, request *http.Request) (signup, error) {
+	app := aero.New()
+	var data signup
+	var bindErr error
+
+	app.Handle(request.Method, "/signup/:id", func(ctx aero.Context) error {
+		bindErr = ctx.Bind(&data)
+		return nil
+	})
+
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusOK)
+	return data, bindErr
+}
+
+func TestBindJSON(t *testing.T) {
+	body := `{"name": "alice", "email": "alice@example.com", "plan": "pro", "age": 30, "tags": ["a"]}`
+	request := httptest.NewRequest(http.MethodPost, "/signup/42?page=2&timeout=5s&verbose=true", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	data, err := bind(t, request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, data.ID, 42)
+	assert.Equal(t, data.Name, "alice")
+	assert.Equal(t, data.Email, "alice@example.com")
+	assert.Equal(t, data.Plan, "pro")
+	assert.Equal(t, *data.Age, 30)
+	assert.DeepEqual(t, data.Tags, []string{"a"})
+	assert.Equal(t, data.Page, 2)
+	assert.Equal(t, data.Timeout, 5*time.Second)
+	assert.True(t, data.Verbose)
+}
+
+func TestBindForm(t *testing.T) {
+	body := "name=bob&email=bob%40example.com&tag=x&tag=y"
+	request := httptest.NewRequest(http.MethodPost, "/signup/1", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data, err := bind(t, request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, data.Name, "bob")
+	assert.Equal(t, data.Email, "bob@example.com")
+	assert.DeepEqual(t, data.Tags, []string{"x", "y"})
+	assert.Nil(t, data.Age)
+}
+
+func TestBindMultipart(t *testing.T) {
+	type upload struct {
+		Title  string                `form:"title" validate:"required"`
+		Avatar *multipart.FileHeader `form:"avatar" validate:"required"`
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	assert.Nil(t, writer.WriteField("title", "Me"))
+	file, err := writer.CreateFormFile("avatar", "avatar.png")
+	assert.Nil(t, err)
+	_, err = file.Write([]byte("image data"))
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	app := aero.New()
+
+	app.Post("/upload", func(ctx aero.Context) error {
+		var data upload
+		err := ctx.Bind(&data)
+
+		if err != nil {
+			return err
+		}
+
+		return ctx.Text(data.Title + " " + data.Avatar.Filename)
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/upload", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "Me avatar.png")
+}
+
+func TestBindValidation(t *testing.T) {
+	body := `{"name": "al", "email": "not an email", "plan": "gold", "age": 12, "tags": ["a", "b", "c"]}`
+	request := httptest.NewRequest(http.MethodPost, "/signup/abc", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	_, err := bind(t, request)
+
+	invalid := &aero.ValidationError{}
+	assert.True(t, errors.As(err, &invalid))
+
+	assert.DeepEqual(t, invalid.Fields, []aero.FieldError{
+		{Field: "id", Rule: "type", Message: "must be of type int"},
+		{Field: "name", Rule: "min", Param: "3", Message: "must be at least 3 characters long"},
+		{Field: "email", Rule: "email", Message: "must be a valid email address"},
+		{Field: "plan", Rule: "oneof", Param: "free pro", Message: "must be one of: free, pro"},
+		{Field: "age", Rule: "min", Param: "18", Message: "must be at least 18"},
+		{Field: "tags", Rule: "max", Param: "2", Message: "must be at most 2 items"},
+	})
+
+	request = httptest.NewRequest(http.MethodPost, "/signup/1", strings.NewReader(`{}`))
+	request.Header.Set("Content-Type", "application/json")
+	_, err = bind(t, request)
+	assert.Contains(t, err.Error(), "name is required")
+	assert.Contains(t, err.Error(), "email is required")
+}
+
+func TestBindErrors(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/signup/1", strings.NewReader(`{"name": 5}`))
+	request.Header.Set("Content-Type", "application/json")
+	_, err := bind(t, request)
+	invalid := &aero.ValidationError{}
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, invalid.Fields[0].Field, "name")
+	assert.Equal(t, invalid.Fields[0].Rule, "type")
+
+	request = httptest.NewRequest(http.MethodPost, "/signup/1", strings.NewReader(`<xml/>`))
+	request.Header.Set("Content-Type", "application/xml")
+	_, err = bind(t, request)
+	assert.True(t, errors.Is(err, aero.ErrUnsupportedContentType))
+
+	app := aero.New()
+
+	app.Get("/", func(ctx aero.Context) error {
+		var data signup
+		assert.True(t, errors.Is(ctx.Bind(data), aero.ErrInvalidBindTarget))
+		return nil
+	})
+
+	test(app, "/")
+}
+
+func TestValidate(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"required"`
+	}
+
+	type user struct {
+		Name    string  `validate:"required"`
+		Address address `json:"address"`
+	}
+
+	err := aero.Validate(user{Name: "alice"})
+	invalid := &aero.ValidationError{}
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, invalid.Fields[0].Field, "address.city")
+
+	assert.Nil(t, aero.Validate(&user{Name: "alice", Address: address{City: "Tokyo"}}))
+	assert.True(t, errors.Is(aero.Validate(42), aero.ErrInvalidBindTarget))
+
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, r.(error).Error(), "Unknown validation rule 'between'")
+	}()
+
+	type invalidRule struct {
+		Name string `validate:"between=1 5"`
+	}
+
+	_ = aero.Validate(invalidRule{})
+}
+
+func TestValidateUnsupportedType(t *testing.T) {
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, r.(error).Error(), "Validation rule 'min' is not supported for type bool")
+	}()
+
+	type settings struct {
+		Enabled bool `validate:"min=1"`
+	}
+
+	_ = aero.Validate(settings{Enabled: true})
+}
@@ -0,0 +1,149 @@
+package aero
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultCompressionLevel selects the default level of a compressor
+// when the configuration doesn't define a level for its encoding.
+const DefaultCompressionLevel = -1
+
+// CompressionWriter compresses the data written to it.
+// Flush is used by streamed responses to send pending data and
+// Reset allows pooled writers to be reused for other responses.
+type CompressionWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// Compressor creates a writer that compresses
+// the response body with the given level.
+type Compressor func(writer io.Writer, level int) (CompressionWriter, error)
+
+// compressor is a compressor registered for a content encoding.
+// Writers are pooled per content encoding.
+type compressor struct {
+	encoding string
+	create   Compressor
+	pool     sync.Pool
+}
+
+// RegisterCompressor registers the compressor for the given content encoding.
+// If the client accepts multiple encodings equally, compressors registered
+// later are preferred over the built-in br, gzip and deflate compressors.
+// Registering an encoding again replaces it, e.g. to use a different library.
+func (app *Application) RegisterCompressor(encoding string, create Compressor) {
+	encoding = strings.ToLower(encoding)
+	registered := &compressor{
+		encoding: encoding,
+		create:   create,
+	}
+
+	for i, existing := range app.compressors {
+		if existing.encoding == encoding {
+			app.compressors[i] = registered
+			return
+		}
+	}
+
+	app.compressors = append(app.compressors, registered)
+}
+
+// negotiateCompression returns the compressor with the highest quality
+// in the Accept-Encoding header or nil if the response shouldn't be compressed.
+func (app *Application) negotiateCompression(acceptEncoding string) *compressor {
+	if acceptEncoding == "" {
+		return nil
+	}
+
+	encodings := parseAccept(acceptEncoding)
+	var best *compressor
+	bestQuality := 0.0
+
+	for _, compressor := range app.compressors {
+		quality := encodingQuality(encodings, compressor.encoding)
+
+		if quality > 0 && quality >= bestQuality {
+			best = compressor
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// acquireCompressionWriter returns a clean writer for the encoding from the pool.
+func (app *Application) acquireCompressionWriter(compressor *compressor, response io.Writer) (CompressionWriter, error) {
+	obj := compressor.pool.Get()
+
+	if obj == nil {
+		level, exists := app.Config.Compression.Levels[compressor.encoding]
+
+		if !exists {
+			level = DefaultCompressionLevel
+		}
+
+		return compressor.create(response, level)
+	}
+
+	writer := obj.(CompressionWriter)
+	writer.Reset(response)
+	return writer, nil
+}
+
+// encodingQuality returns the quality of the encoding in the Accept-Encoding header.
+func encodingQuality(encodings []acceptedValue, encoding string) float64 {
+	quality := 0.0
+	exact := false
+
+	for _, accepted := range encodings {
+		switch {
+		case accepted.value == encoding:
+			quality = accepted.quality
+			exact = true
+		case accepted.value == "*" && !exact:
+			quality = accepted.quality
+		}
+	}
+
+	return quality
+}
+
+// compressGzip creates a gzip writer.
+func compressGzip(writer io.Writer, level int) (CompressionWriter, error) {
+	compressed, err := gzip.NewWriterLevel(writer, level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return compressed, nil
+}
+
+// compressDeflate creates a writer for the deflate encoding,
+// which is the zlib format according to the HTTP specification.
+func compressDeflate(writer io.Writer, level int) (CompressionWriter, error) {
+	compressed, err := zlib.NewWriterLevel(writer, level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return compressed, nil
+}
+
+// compressBrotli creates a writer for the br encoding.
+func compressBrotli(writer io.Writer, level int) (CompressionWriter, error) {
+	if level == DefaultCompressionLevel {
+		level = brotli.DefaultCompression
+	}
+
+	return brotli.NewWriterLevel(writer, level), nil
+}
@@ -0,0 +1,158 @@
+package aero_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+	"github.com/andybalholm/brotli"
+)
+
+// reverseWriter is a fake compression writer that reverses the body.
+type reverseWriter struct {
+	writer io.Writer
+	buffer []byte
+}
+
+func (writer *reverseWriter) Write(data []byte) (int, error) {
+	writer.buffer = append(writer.buffer, data...)
+	return len(data), nil
+}
+
+func (writer *reverseWriter) Close() error {
+	for i, j := 0, len(writer.buffer)-1; i < j; i, j = i+1, j-1 {
+		writer.buffer[i], writer.buffer[j] = writer.buffer[j], writer.buffer[i]
+	}
+
+	_, err := writer.writer.Write(writer.buffer)
+	return err
+}
+
+func (writer *reverseWriter) Flush() error {
+	return nil
+}
+
+func (writer *reverseWriter) Reset(w io.Writer) {
+	writer.writer = w
+	writer.buffer = writer.buffer[:0]
+}
+
+func TestCompression(t *testing.T) {
+	app := aero.New()
+	body := strings.Repeat(helloWorld, 100)
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(body)
+	})
+
+	app.Get("/image", func(ctx aero.Context) error {
+		ctx.Response().SetHeader("Content-Type", "image/png")
+		return ctx.String(body)
+	})
+
+	decode := func(encoding string, data []byte) string {
+		var reader io.Reader = bytes.NewReader(data)
+		var err error
+
+		switch encoding {
+		case "gzip":
+			reader, err = gzip.NewReader(reader)
+		case "deflate":
+			reader, err = zlib.NewReader(reader)
+		case "br":
+			reader = brotli.NewReader(reader)
+		}
+
+		assert.Nil(t, err)
+		decoded, err := ioutil.ReadAll(reader)
+		assert.Nil(t, err)
+		return string(decoded)
+	}
+
+	tests := []struct {
+		path           string
+		acceptEncoding string
+		encoding       string
+	}{
+		{"/", "", ""},
+		{"/", "gzip", "gzip"},
+		{"/", "deflate", "deflate"},
+		{"/", "br", "br"},
+		{"/", "zstd", ""},
+		{"/", "gzip, deflate", "gzip"},
+		{"/", "gzip, deflate, br", "br"},
+		{"/", "gzip, deflate, br, zstd", "br"},
+		{"/", "gzip;q=0.5, deflate", "deflate"},
+		{"/", "GZIP;q=0.5, identity", "gzip"},
+		{"/", "br;q=0.5, gzip", "gzip"},
+		{"/", "*", "br"},
+		{"/", "*, br;q=0", "gzip"},
+		{"/", "gzip;q=0, deflate;q=0", ""},
+		{"/", "x-unknown", ""},
+		{"/image", "gzip", ""},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, test.path, nil)
+		request.Header.Set("Accept-Encoding", test.acceptEncoding)
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Header().Get("Content-Encoding"), test.encoding)
+		assert.Equal(t, decode(test.encoding, response.Body.Bytes()), body)
+
+		if test.path == "/" {
+			assert.Equal(t, response.Header().Get("Vary"), "Accept-Encoding")
+		} else {
+			assert.Equal(t, response.Header().Get("Vary"), "")
+		}
+	}
+}
+
+func TestCompressionRegister(t *testing.T) {
+	app := aero.New()
+	body := strings.Repeat("ab", 200)
+
+	app.RegisterCompressor("x-reverse", func(writer io.Writer, level int) (aero.CompressionWriter, error) {
+		assert.Equal(t, level, aero.DefaultCompressionLevel)
+		return &reverseWriter{writer: writer}, nil
+	})
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(body)
+	})
+
+	for i := 0; i < 3; i++ {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("Accept-Encoding", "gzip, x-reverse")
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+
+		assert.Equal(t, response.Header().Get("Content-Encoding"), "x-reverse")
+		assert.Equal(t, response.Body.String(), strings.Repeat("ba", 200))
+	}
+}
+
+func TestCompressionLevel(t *testing.T) {
+	app := aero.New()
+	app.Config.Compression.Levels["gzip"] = 42
+
+	app.Get("/", func(ctx aero.Context) error {
+		err := ctx.Text(strings.Repeat(helloWorld, 100))
+		assert.NotNil(t, err)
+		return err
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	app.ServeHTTP(httptest.NewRecorder(), request)
+}
@@ -0,0 +1,173 @@
+package aero
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetETag sets the entity tag that identifies the current version
+// of the resource. Unquoted tags are quoted, weak tags can be
+// given in their header form, e.g. W/"v42". Without an explicit
+// entity tag, Bytes uses a hash of the response body.
+func (ctx *context) SetETag(etag string) {
+	if !strings.HasPrefix(etag, `"`) && !strings.HasPrefix(etag, `W/"`) {
+		etag = strconv.Quote(etag)
+	}
+
+	ctx.response.SetHeader(etagHeader, etag)
+}
+
+// SetLastModified sets the time at which the resource was last modified.
+// It is used to answer If-Modified-Since and If-Unmodified-Since requests.
+func (ctx *context) SetLastModified(modified time.Time) {
+	if modified.IsZero() {
+		ctx.response.inner.Header().Del(lastModifiedHeader)
+		return
+	}
+
+	ctx.response.SetHeader(lastModifiedHeader, modified.UTC().Format(http.TimeFormat))
+}
+
+// Precondition checks the If-Match, If-Unmodified-Since and If-None-Match
+// headers against the entity tag and modification time of the resource.
+// If a precondition fails, it responds with 412 Precondition Failed and
+// returns ErrPreconditionFailed. Call it after SetETag or SetLastModified
+// and before modifying the resource to implement optimistic concurrency.
+func (ctx *context) Precondition() error {
+	if ctx.preconditionStatus() != http.StatusPreconditionFailed {
+		return nil
+	}
+
+	return ctx.Error(http.StatusPreconditionFailed, ErrPreconditionFailed)
+}
+
+// preconditionStatus evaluates the conditional request headers in the
+// order defined by RFC 7232 and returns 304 or 412 if the request
+// shouldn't be processed normally or 0 otherwise.
+func (ctx *context) preconditionStatus() int {
+	request := ctx.request.inner
+	header := ctx.response.inner.Header()
+	etag := header.Get(etagHeader)
+	lastModified, _ := http.ParseTime(header.Get(lastModifiedHeader))
+	safe := request.Method == http.MethodGet || request.Method == http.MethodHead
+	ifMatch := request.Header.Get(ifMatchHeader)
+
+	if ifMatch != "" {
+		if !matchETag(ifMatch, etag, false) {
+			return http.StatusPreconditionFailed
+		}
+	} else if modifiedSince(request.Header.Get(ifUnmodifiedSinceHeader), lastModified) {
+		return http.StatusPreconditionFailed
+	}
+
+	ifNoneMatch := request.Header.Get(ifNoneMatchHeader)
+
+	if ifNoneMatch != "" {
+		if !matchETag(ifNoneMatch, etag, true) {
+			return 0
+		}
+
+		if safe {
+			return http.StatusNotModified
+		}
+
+		return http.StatusPreconditionFailed
+	}
+
+	since, err := http.ParseTime(request.Header.Get(ifModifiedSinceHeader))
+
+	if safe && err == nil && !lastModified.IsZero() && !lastModified.Truncate(time.Second).After(since) {
+		return http.StatusNotModified
+	}
+
+	return 0
+}
+
+// hasValidators reports whether the handler set an entity tag or modification time.
+func (ctx *context) hasValidators() bool {
+	header := ctx.response.inner.Header()
+	return header.Get(etagHeader) != "" || header.Get(lastModifiedHeader) != ""
+}
+
+// matchETag reports whether the list of entity tags in a conditional
+// header matches the entity tag. The weak comparison ignores the W/
+// prefix, the strong comparison never matches weak entity tags.
+// The wildcard * matches any existing entity tag.
+func matchETag(list string, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+
+	etagWeak := strings.HasPrefix(etag, "W/")
+
+	if etagWeak && !weak {
+		return false
+	}
+
+	opaque := strings.TrimPrefix(etag, "W/")
+
+	for list != "" {
+		list = strings.TrimLeft(list, " \t,")
+
+		if list == "" {
+			break
+		}
+
+		isWeak := strings.HasPrefix(list, "W/")
+
+		if isWeak {
+			list = list[2:]
+		}
+
+		var candidate string
+
+		if strings.HasPrefix(list, `"`) {
+			end := strings.IndexByte(list[1:], '"')
+
+			if end == -1 {
+				return false
+			}
+
+			candidate = list[:end+2]
+			list = list[end+2:]
+		} else {
+			// Unquoted entity tags as sent by older versions of Aero
+			end := strings.IndexByte(list, ',')
+
+			if end == -1 {
+				end = len(list)
+			}
+
+			candidate = strconv.Quote(strings.TrimSpace(list[:end]))
+			list = list[end:]
+		}
+
+		if (weak || !isWeak) && candidate == opaque {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modifiedSince reports whether the modification time is after the
+// HTTP date. Invalid dates and unknown modification times return false.
+func modifiedSince(date string, modified time.Time) bool {
+	if date == "" || modified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(date)
+
+	if err != nil {
+		return false
+	}
+
+	return modified.Truncate(time.Second).After(since)
+}
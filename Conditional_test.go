@@ -0,0 +1,135 @@
+package aero_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+func TestConditionalGet(t *testing.T) {
+	app := aero.New()
+	text := strings.Repeat(helloWorld, 100)
+	modified := time.Date(2020, time.January, 1, 12, 0, 0, 500, time.UTC)
+	etag := strconv.Quote(aero.ETagString(text))
+
+	app.Get("/", func(ctx aero.Context) error {
+		ctx.SetLastModified(modified)
+		return ctx.Text(text)
+	})
+
+	app.Get("/small", func(ctx aero.Context) error {
+		ctx.SetETag("v1")
+		return ctx.Text("small")
+	})
+
+	app.Get("/weak", func(ctx aero.Context) error {
+		ctx.SetETag(`W/"v1"`)
+		return ctx.Text("weak")
+	})
+
+	app.Get("/missing", func(ctx aero.Context) error {
+		ctx.SetETag("v1")
+		return ctx.Error(http.StatusNotFound)
+	})
+
+	lastModified := modified.Format(http.TimeFormat)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+
+	tests := []struct {
+		path    string
+		headers map[string]string
+		code    int
+	}{
+		{"/", nil, http.StatusOK},
+		{"/", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"/", map[string]string{"If-None-Match": `"a", ` + etag}, http.StatusNotModified},
+		{"/", map[string]string{"If-None-Match": "W/" + etag}, http.StatusNotModified},
+		{"/", map[string]string{"If-None-Match": aero.ETagString(text)}, http.StatusNotModified},
+		{"/", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"/", map[string]string{"If-None-Match": `"a", "b"`}, http.StatusOK},
+		{"/", map[string]string{"If-Modified-Since": lastModified}, http.StatusNotModified},
+		{"/", map[string]string{"If-Modified-Since": before}, http.StatusOK},
+		{"/", map[string]string{"If-Modified-Since": "invalid"}, http.StatusOK},
+		{"/", map[string]string{"If-None-Match": `"a"`, "If-Modified-Since": lastModified}, http.StatusOK},
+		{"/", map[string]string{"If-Match": etag}, http.StatusOK},
+		{"/", map[string]string{"If-Match": `"a"`}, http.StatusPreconditionFailed},
+		{"/", map[string]string{"If-Unmodified-Since": before}, http.StatusPreconditionFailed},
+		{"/", map[string]string{"If-Unmodified-Since": lastModified}, http.StatusOK},
+		{"/small", map[string]string{"If-None-Match": `"v1"`}, http.StatusNotModified},
+		{"/weak", map[string]string{"If-None-Match": `"v1"`}, http.StatusNotModified},
+		{"/weak", map[string]string{"If-Match": `W/"v1"`}, http.StatusPreconditionFailed},
+		{"/missing", map[string]string{"If-None-Match": `"v1"`}, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, test.path, nil)
+
+		for key, value := range test.headers {
+			request.Header.Set(key, value)
+		}
+
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, test.code)
+
+		if test.code == http.StatusNotModified {
+			assert.Equal(t, response.Body.Len(), 0)
+			assert.NotEqual(t, response.Header().Get("ETag"), "")
+		}
+	}
+
+	response := test(app, "/")
+	assert.Equal(t, response.Header().Get("Last-Modified"), lastModified)
+	assert.Equal(t, response.Header().Get("ETag"), strconv.Quote(aero.ETagString(text)+"-gzip"))
+}
+
+func TestConditionalPut(t *testing.T) {
+	app := aero.New()
+	version := 1
+
+	app.Put("/article", func(ctx aero.Context) error {
+		ctx.SetETag(strconv.Itoa(version))
+		err := ctx.Precondition()
+
+		if err != nil {
+			assert.Equal(t, err.Error(), aero.ErrPreconditionFailed.Error())
+			return err
+		}
+
+		version++
+		ctx.SetETag(strconv.Itoa(version))
+		return ctx.Text("updated")
+	})
+
+	tests := []struct {
+		headers map[string]string
+		code    int
+	}{
+		{map[string]string{"If-Match": `"1"`}, http.StatusOK},
+		{map[string]string{"If-Match": `"1"`}, http.StatusPreconditionFailed},
+		{map[string]string{"If-Match": `"3", "2"`}, http.StatusOK},
+		{map[string]string{"If-None-Match": "*"}, http.StatusPreconditionFailed},
+		{map[string]string{"If-Match": "*"}, http.StatusOK},
+		{nil, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodPut, "/article", nil)
+
+		for key, value := range test.headers {
+			request.Header.Set(key, value)
+		}
+
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, test.code)
+	}
+
+	assert.Equal(t, version, 5)
+}
@@ -6,12 +6,46 @@ import (
 	"time"
 )
 
+// Path policies define how non-canonical request paths are handled.
+// Paths are non-canonical if they contain duplicate slashes,
+// . or .. segments or a trailing slash that doesn't match the route.
+const (
+	// PathsLenient serves non-canonical paths like their canonical form.
+	PathsLenient = "lenient"
+
+	// PathsStrict only serves paths that exactly match a route.
+	PathsStrict = "strict"
+
+	// PathsRedirect redirects non-canonical paths to their canonical form.
+	PathsRedirect = "redirect"
+)
+
 // Configuration represents the data in your config.json file.
 type Configuration struct {
-	Push     []string             `json:"push"`
-	GZip     bool                 `json:"gzip"`
-	Ports    PortConfiguration    `json:"ports"`
-	Timeouts TimeoutConfiguration `json:"timeouts"`
+	Push        []string                 `json:"push"`
+	GZip        bool                     `json:"gzip"`
+	Compression CompressionConfiguration `json:"compression"`
+	Limits      LimitConfiguration       `json:"limits"`
+	Ports       PortConfiguration        `json:"ports"`
+	Routing     RoutingConfiguration     `json:"routing"`
+	Timeouts    TimeoutConfiguration     `json:"timeouts"`
+}
+
+// CompressionConfiguration lets you configure the compression level
+// for each content encoding, e.g. "gzip": 6. Encodings without a level
+// use the default level of their compressor. Streaming enables the
+// compression of streamed responses like Reader and EventStream.
+type CompressionConfiguration struct {
+	Levels    map[string]int `json:"levels"`
+	Streaming bool           `json:"streaming"`
+}
+
+// LimitConfiguration lets you configure the maximum request body size in bytes.
+// DecodedBody limits the size after gzip or deflate decompression.
+// A limit of 0 means that there is no limit.
+type LimitConfiguration struct {
+	Body        int64 `json:"body"`
+	DecodedBody int64 `json:"decodedBody"`
 }
 
 // PortConfiguration lets you configure the ports that Aero will listen on.
@@ -20,6 +54,12 @@ type PortConfiguration struct {
 	HTTPS int `json:"https"`
 }
 
+// RoutingConfiguration lets you configure how request paths are matched.
+type RoutingConfiguration struct {
+	Paths           string `json:"paths"`
+	CaseInsensitive bool   `json:"caseInsensitive"`
+}
+
 // TimeoutConfiguration lets you configure the different timeout durations.
 type TimeoutConfiguration struct {
 	Idle       time.Duration `json:"idle"`
@@ -32,8 +72,18 @@ type TimeoutConfiguration struct {
 func (config *Configuration) Reset() {
 	config.Push = []string{}
 	config.GZip = true
+	config.Compression.Levels = map[string]int{
+		"gzip":    6,
+		"deflate": 6,
+		"br":      4,
+		"zstd":    3,
+	}
+	config.Limits.Body = 10 << 20
+	config.Limits.DecodedBody = 10 << 20
 	config.Ports.HTTP = 4000
 	config.Ports.HTTPS = 4001
+	config.Routing.Paths = PathsLenient
+	config.Routing.CaseInsensitive = false
 	config.Timeouts.Idle = 180 * time.Second
 	config.Timeouts.Write = 120 * time.Second
 	config.Timeouts.ReadHeader = 5 * time.Second
@@ -18,6 +18,7 @@ import (
 	"github.com/aerogo/session"
 	"github.com/akyoto/color"
 	"github.com/akyoto/stringutils/unsafe"
+	"github.com/akyoto/uuid"
 )
 
 const (
@@ -30,10 +31,12 @@ const (
 	// in the smallest case.
 	gzipThreshold = 256
 
-	// maxParams defines the maximum number of parameters per route.
+	// maxParams defines the number of parameters per route
+	// that can be stored without an allocation.
 	maxParams = 16
 
-	// maxModifiers defines the maximum number of modifiers per context.
+	// maxModifiers defines the number of modifiers per context
+	// that can be stored without an allocation.
 	maxModifiers = 4
 )
 
@@ -41,11 +44,14 @@ const (
 type Context interface {
 	AddModifier(Modifier)
 	App() *Application
+	Bind(interface{}) error
 	Bytes([]byte) error
 	Close()
 	CSS(string) error
 	Get(string) string
 	GetInt(string) (int, error)
+	GetInt64(string) (int64, error)
+	GetUUID(string) (uuid.UUID, error)
 	Error(int, ...interface{}) error
 	EventStream(stream *event.Stream) error
 	File(string) error
@@ -54,7 +60,10 @@ type Context interface {
 	IP() string
 	JavaScript(string) error
 	JSON(interface{}) error
+	Negotiate(interface{}) error
+	Params() []Parameter
 	Path() string
+	Precondition() error
 	Query(param string) string
 	ReadAll(io.Reader) error
 	Reader(io.Reader) error
@@ -63,32 +72,45 @@ type Context interface {
 	RemoteIP() string
 	Request() Request
 	Response() Response
+	Route() *Route
 	Session() *session.Session
+	SetETag(string)
+	SetLastModified(time.Time)
 	SetStatus(int)
 	Status() int
+	Stream() (StreamWriter, error)
 	String(string) error
 	Text(string) error
+	URLFor(string, ...string) (string, error)
 }
 
 // context represents a request & response context.
 type context struct {
-	app           *Application
-	status        int
-	request       request
-	response      response
-	session       *session.Session
-	handler       Handler
-	paramNames    [maxParams]string
-	paramValues   [maxParams]string
-	paramCount    int
-	modifiers     [maxModifiers]Modifier
-	modifierCount int
+	app            *Application
+	status         int
+	request        request
+	response       response
+	session        *session.Session
+	handler        Handler
+	route          *Route
+	paramNames     [maxParams]string
+	paramValues    [maxParams]string
+	paramCount     int
+	extraParams    []Parameter
+	modifiers      [maxModifiers]Modifier
+	modifierCount  int
+	extraModifiers []Modifier
 }
 
 // AddModifier adds a modifier that can change the response body
 // contents of in-memory responses before the actual response happens.
 func (ctx *context) AddModifier(modifier Modifier) {
-	ctx.modifiers[ctx.modifierCount] = modifier
+	if ctx.modifierCount < maxModifiers {
+		ctx.modifiers[ctx.modifierCount] = modifier
+	} else {
+		ctx.extraModifiers = append(ctx.extraModifiers, modifier)
+	}
+
 	ctx.modifierCount++
 }
 
@@ -97,7 +119,7 @@ func (ctx *context) App() *Application {
 	return ctx.app
 }
 
-// Bytes responds either with raw text or gzipped if the
+// Bytes responds either with raw text or compressed if the
 // text length is greater than the gzip threshold. Requires a byte slice.
 func (ctx *context) Bytes(body []byte) error {
 	// If the request has been canceled by the client, stop.
@@ -107,36 +129,76 @@ func (ctx *context) Bytes(body []byte) error {
 
 	// If we registered any response body modifiers, invoke them.
 	if ctx.modifierCount > 0 {
-		for i := 0; i < ctx.modifierCount; i++ {
+		for i := 0; i < ctx.modifierCount && i < maxModifiers; i++ {
 			body = ctx.modifiers[i](body)
 		}
+
+		for _, modifier := range ctx.extraModifiers {
+			body = modifier(body)
+		}
+	}
+
+	header := ctx.response.inner.Header()
+	method := ctx.request.inner.Method
+	small := len(body) < gzipThreshold
+
+	// Range requests are answered with parts of the uncompressed body
+	ranged := false
+
+	if (method == http.MethodGet || method == http.MethodHead) && ctx.status == http.StatusOK {
+		header.Set(acceptRangesHeader, "bytes")
+		ranged = method == http.MethodGet && ctx.request.inner.Header.Get(rangeHeader) != ""
 	}
 
 	// Small response
-	if len(body) < gzipThreshold {
+	if small && !ranged && !ctx.hasValidators() {
+		if len(body) > 0 {
+			header.Set(contentLengthHeader, strconv.Itoa(len(body)))
+		}
+
 		ctx.response.inner.WriteHeader(ctx.status)
 		_, err := ctx.response.inner.Write(body)
 		return err
 	}
 
-	// ETag generation
-	etag := ETag(body)
+	// Content type
+	contentType := header.Get(contentTypeHeader)
+	isMediaType := isMedia(contentType)
+
+	// Compression
+	var compressor *compressor
 
-	// If client cache is up to date, send 304 with no response body.
-	clientETag := ctx.request.Header(ifNoneMatchHeader)
+	if !small && ctx.app.Config.GZip && canCompress(contentType) {
+		header.Add(varyHeader, acceptEncodingHeader)
 
-	if etag == clientETag {
-		ctx.response.inner.WriteHeader(304)
-		return nil
+		if !ranged {
+			compressor = ctx.app.negotiateCompression(ctx.request.Header(acceptEncodingHeader))
+		}
 	}
 
-	// Set ETag
-	header := ctx.response.inner.Header()
-	header.Set(etagHeader, etag)
+	// ETag generation, every content encoding is a separate representation
+	if !small && header.Get(etagHeader) == "" {
+		etag := ETag(body)
 
-	// Content type
-	contentType := header.Get(contentTypeHeader)
-	isMediaType := isMedia(contentType)
+		if compressor != nil {
+			etag += "-" + compressor.encoding
+		}
+
+		header.Set(etagHeader, strconv.Quote(etag))
+	}
+
+	// If client cache is up to date, send 304 with no response body.
+	// Failed preconditions are answered with 412. Other methods
+	// have to check their preconditions before making changes.
+	if (method == http.MethodGet || method == http.MethodHead) && ctx.status >= 200 && ctx.status < 300 {
+		status := ctx.preconditionStatus()
+
+		if status != 0 {
+			header.Del(contentTypeHeader)
+			ctx.response.inner.WriteHeader(status)
+			return nil
+		}
+	}
 
 	// Cache control header
 	if isMediaType {
@@ -145,30 +207,48 @@ func (ctx *context) Bytes(body []byte) error {
 		header.Set(cacheControlHeader, cacheControlAlwaysValidate)
 	}
 
-	// No GZip?
-	clientSupportsGZip := strings.Contains(ctx.request.Header(acceptEncodingHeader), "gzip")
+	// Partial content, unless If-Range refers to an outdated version
+	if ranged && ctx.ifRange() {
+		served, err := ctx.writeRanges(body, contentType)
+
+		if served {
+			return err
+		}
+	}
+
+	if compressor == nil {
+		if len(body) > 0 {
+			header.Set(contentLengthHeader, strconv.Itoa(len(body)))
+		}
 
-	if !ctx.app.Config.GZip || !clientSupportsGZip || !canCompress(contentType) {
-		header.Set(contentLengthHeader, strconv.Itoa(len(body)))
 		ctx.response.inner.WriteHeader(ctx.status)
 		_, err := ctx.response.inner.Write(body)
 		return err
 	}
 
-	// GZip
-	header.Set(contentEncodingHeader, contentEncodingGzip)
+	writer, err := ctx.app.acquireCompressionWriter(compressor, ctx.response.inner)
+
+	if err != nil {
+		return err
+	}
+
+	// Compression
+	header.Set(contentEncodingHeader, compressor.encoding)
 	ctx.response.inner.WriteHeader(ctx.status)
 
 	// Write response body
-	writer := ctx.app.acquireGZipWriter(ctx.response.inner)
-	_, err := writer.Write(body)
-	writer.Close()
+	_, err = writer.Write(body)
+	closeErr := writer.Close()
 
 	// Put the writer back into the pool
-	ctx.app.gzipWriterPool.Put(writer)
+	compressor.pool.Put(writer)
+
+	// Return the error value of the first failed call
+	if err != nil {
+		return err
+	}
 
-	// Return the error value of the last Write call
-	return err
+	return closeErr
 }
 
 // Close frees up resources and is automatically called
@@ -220,7 +300,7 @@ func (ctx *context) EventStream(stream *event.Stream) error {
 	defer close(stream.Closed)
 
 	// Flush supported?
-	flusher, ok := ctx.response.inner.(http.Flusher)
+	_, ok := ctx.response.inner.(http.Flusher)
 
 	if !ok {
 		return ctx.Error(http.StatusNotImplemented, "Flushing not supported")
@@ -232,7 +312,14 @@ func (ctx *context) EventStream(stream *event.Stream) error {
 	header.Set(cacheControlHeader, cacheControlNoCache)
 	header.Set(connectionHeader, connectionKeepAlive)
 	header.Set(corsHeader, corsAll)
-	ctx.response.inner.WriteHeader(200)
+	ctx.status = http.StatusOK
+	writer, err := ctx.Stream()
+
+	if err != nil {
+		return err
+	}
+
+	defer writer.Close()
 
 	// Catch disconnect events
 	disconnected := ctx.request.Context().Done()
@@ -251,7 +338,7 @@ func (ctx *context) EventStream(stream *event.Stream) error {
 
 			switch data.(type) {
 			case string, []byte:
-				fmt.Fprintf(ctx.response.inner, "event: %s\ndata: %s\n\n", event.Name, data)
+				fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", event.Name, data)
 
 			default:
 				jsonData, err := json.Marshal(data)
@@ -260,10 +347,14 @@ func (ctx *context) EventStream(stream *event.Stream) error {
 					return err
 				}
 
-				fmt.Fprintf(ctx.response.inner, "event: %s\ndata: %s\n\n", event.Name, jsonData)
+				fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", event.Name, jsonData)
 			}
 
-			flusher.Flush()
+			err = writer.Flush()
+
+			if err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -284,20 +375,54 @@ func (ctx *context) File(file string) error {
 
 // Get retrieves an URL parameter.
 func (ctx *context) Get(param string) string {
-	for i := 0; i < ctx.paramCount; i++ {
+	for i := 0; i < ctx.paramCount && i < maxParams; i++ {
 		if ctx.paramNames[i] == param {
 			return ctx.paramValues[i]
 		}
 	}
 
+	for i := maxParams; i < ctx.paramCount; i++ {
+		if ctx.extraParams[i-maxParams].Name == param {
+			return ctx.extraParams[i-maxParams].Value
+		}
+	}
+
 	return ""
 }
 
+// Params returns all URL parameters in the order they appear in the host and path.
+func (ctx *context) Params() []Parameter {
+	params := make([]Parameter, 0, ctx.paramCount)
+
+	for i := 0; i < ctx.paramCount && i < maxParams; i++ {
+		params = append(params, Parameter{
+			Name:  ctx.paramNames[i],
+			Value: ctx.paramValues[i],
+		})
+	}
+
+	for i := maxParams; i < ctx.paramCount; i++ {
+		params = append(params, ctx.extraParams[i-maxParams])
+	}
+
+	return params
+}
+
 // GetInt retrieves an URL parameter as an integer.
 func (ctx *context) GetInt(param string) (int, error) {
 	return strconv.Atoi(ctx.Get(param))
 }
 
+// GetInt64 retrieves an URL parameter as a 64-bit integer.
+func (ctx *context) GetInt64(param string) (int64, error) {
+	return strconv.ParseInt(ctx.Get(param), 10, 64)
+}
+
+// GetUUID retrieves an URL parameter as a UUID.
+func (ctx *context) GetUUID(param string) (uuid.UUID, error) {
+	return uuid.Parse(ctx.Get(param))
+}
+
 // HasSession indicates whether the client has a valid session or not.
 func (ctx *context) HasSession() bool {
 	if ctx.session != nil {
@@ -368,6 +493,27 @@ func (ctx *context) JSON(value interface{}) error {
 	return ctx.Bytes(bytes)
 }
 
+// Negotiate encodes the value with the registered encoder that best
+// matches the Accept header and responds. If the client doesn't accept
+// any of the registered content types, the response is a 406.
+func (ctx *context) Negotiate(value interface{}) error {
+	ctx.response.inner.Header().Add(varyHeader, acceptHeader)
+	encoder := ctx.app.negotiate(ctx.request.inner.Header.Get(acceptHeader))
+
+	if encoder == nil {
+		return ctx.Error(http.StatusNotAcceptable)
+	}
+
+	body, err := encoder.encode(value)
+
+	if err != nil {
+		return err
+	}
+
+	ctx.response.SetHeader(contentTypeHeader, encoder.contentType)
+	return ctx.Bytes(body)
+}
+
 // Path returns the relative request path, e.g. /blog/post/123.
 func (ctx *context) Path() string {
 	return ctx.request.inner.URL.Path
@@ -387,11 +533,24 @@ func (ctx *context) ReadAll(reader io.Reader) error {
 }
 
 // Reader sends the contents of the io.Reader without creating an in-memory copy.
-// E-Tags will not be generated for the content and compression will not be applied.
+// E-Tags will not be generated for the content and compression will only be
+// applied if streaming compression is enabled in the configuration.
 // Use this function if your reader contains huge amounts of data.
 func (ctx *context) Reader(reader io.Reader) error {
-	_, err := io.Copy(ctx.response.inner, reader)
-	return err
+	stream, err := ctx.Stream()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(stream, reader)
+	closeErr := stream.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return closeErr
 }
 
 // ReadSeeker sends the contents of the io.ReadSeeker without creating an in-memory copy.
@@ -494,19 +653,54 @@ func (ctx *context) Text(text string) error {
 	return ctx.String(text)
 }
 
+// URLFor returns the path of the route with the given name and parameters.
+// Parameters are given as name and value pairs, e.g. "nick", "alice".
+// Names are resolved in the router of the matched route,
+// so handlers of a virtual host find the routes of that host.
+func (ctx *context) URLFor(name string, params ...string) (string, error) {
+	if ctx.route != nil {
+		return ctx.route.router.URL(name, params...)
+	}
+
+	return ctx.app.Router().URL(name, params...)
+}
+
+// Route returns the route that matched the request
+// or nil if the request was not routed to a handler.
+func (ctx *context) Route() *Route {
+	return ctx.route
+}
+
 // Query retrieves the value for the given URL query parameter.
 func (ctx *context) Query(param string) string {
 	return ctx.request.inner.URL.Query().Get(param)
 }
 
 // addParameter adds a new parameter to the context.
+// Parameters beyond maxParams are stored in a slice that
+// only allocates for routes with an unusual number of parameters.
 func (ctx *context) addParameter(name string, value string) {
-	ctx.paramNames[ctx.paramCount] = name
-	ctx.paramValues[ctx.paramCount] = value
+	if ctx.paramCount < maxParams {
+		ctx.paramNames[ctx.paramCount] = name
+		ctx.paramValues[ctx.paramCount] = value
+	} else {
+		index := ctx.paramCount - maxParams
+		ctx.extraParams = append(ctx.extraParams[:index], Parameter{Name: name, Value: value})
+	}
+
 	ctx.paramCount++
 }
 
-// canCompress returns whether the given content type should be compressed via gzip.
+// paramValue returns the value of the parameter at the given index.
+func (ctx *context) paramValue(index int) string {
+	if index < maxParams {
+		return ctx.paramValues[index]
+	}
+
+	return ctx.extraParams[index-maxParams].Value
+}
+
+// canCompress returns whether the given content type should be compressed.
 func canCompress(contentType string) bool {
 	switch {
 	case strings.HasPrefix(contentType, "image/") && contentType != contentTypeSVG:
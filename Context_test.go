@@ -201,6 +201,7 @@ func TestContextSessionValidCookie(t *testing.T) {
 
 func TestContextContentTypes(t *testing.T) {
 	app := aero.New()
+	app.Config.GZip = false
 
 	app.Get("/json", func(ctx aero.Context) error {
 		return ctx.JSON(app.Config)
@@ -268,6 +269,7 @@ func TestContextContentTypes(t *testing.T) {
 
 func TestContextReader(t *testing.T) {
 	app := aero.New()
+	app.Config.GZip = false
 	config, err := json.Marshal(app.Config)
 	assert.Nil(t, err)
 
@@ -576,3 +578,82 @@ func TestBigResponse304(t *testing.T) {
 	assert.Equal(t, response.Code, 304)
 	assert.Equal(t, response.Body.String(), "")
 }
+
+func TestContextTypedParameters(t *testing.T) {
+	app := aero.New()
+	id := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	app.Get("/post/:id<int>", func(ctx aero.Context) error {
+		number, err := ctx.GetInt64("id")
+		assert.Nil(t, err)
+		return ctx.Text(strconv.FormatInt(number*2, 10))
+	})
+
+	app.Get("/post/:uuid<uuid>", func(ctx aero.Context) error {
+		value, err := ctx.GetUUID("uuid")
+		assert.Nil(t, err)
+		return ctx.Text(value.String())
+	})
+
+	response := test(app, "/post/3000000000")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "6000000000")
+
+	response = test(app, "/post/"+id)
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), id)
+
+	response = test(app, "/post/abc")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
+
+func TestContextParams(t *testing.T) {
+	app := aero.New()
+	path := ""
+	expected := []aero.Parameter{}
+
+	for i := 0; i < 20; i++ {
+		name := "p" + strconv.Itoa(i)
+		path += "/:" + name
+		expected = append(expected, aero.Parameter{Name: name, Value: strconv.Itoa(i)})
+	}
+
+	app.Get(path, func(ctx aero.Context) error {
+		assert.DeepEqual(t, ctx.Params(), expected)
+		return ctx.Text(ctx.Get("p0") + ctx.Get("p19"))
+	})
+
+	url := ""
+
+	for i := 0; i < 20; i++ {
+		url += "/" + strconv.Itoa(i)
+	}
+
+	for i := 0; i < 2; i++ {
+		response := test(app, url)
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), "019")
+	}
+}
+
+func TestContextModifiers(t *testing.T) {
+	app := aero.New()
+
+	app.Get("/", func(ctx aero.Context) error {
+		for i := 0; i < 6; i++ {
+			digit := strconv.Itoa(i)
+
+			ctx.AddModifier(func(body []byte) []byte {
+				return append(body, digit...)
+			})
+		}
+
+		return ctx.Text("")
+	})
+
+	for i := 0; i < 2; i++ {
+		response := test(app, "/")
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), "012345")
+	}
+}
@@ -0,0 +1,263 @@
+package aero
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoder converts a value to the response body for a content type.
+type Encoder func(interface{}) ([]byte, error)
+
+// encoder is an encoder registered for a content type.
+type encoder struct {
+	contentType string
+	mediaType   string
+	encode      Encoder
+}
+
+// acceptedValue is a value of an Accept or Accept-Encoding header,
+// e.g. the media range text/* or the encoding gzip, with its quality.
+type acceptedValue struct {
+	value   string
+	quality float64
+}
+
+// RegisterEncoder registers the encoder that ctx.Negotiate uses
+// for the given content type, e.g. "application/cbor".
+// Encoders registered earlier are preferred if the client
+// accepts multiple content types equally.
+// Registering a content type again replaces its encoder.
+func (app *Application) RegisterEncoder(contentType string, encode Encoder) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	if err != nil {
+		panic(fmt.Errorf("Invalid content type '%s': %w", contentType, err))
+	}
+
+	for i := range app.encoders {
+		if app.encoders[i].mediaType == mediaType {
+			app.encoders[i].contentType = contentType
+			app.encoders[i].encode = encode
+			return
+		}
+	}
+
+	app.encoders = append(app.encoders, encoder{
+		contentType: contentType,
+		mediaType:   mediaType,
+		encode:      encode,
+	})
+}
+
+// negotiate returns the encoder with the highest quality in the Accept header
+// or nil if the client doesn't accept any of the registered content types.
+func (app *Application) negotiate(accept string) *encoder {
+	if len(app.encoders) == 0 {
+		return nil
+	}
+
+	accept = strings.TrimSpace(accept)
+
+	if accept == "" {
+		return &app.encoders[0]
+	}
+
+	ranges := parseAccept(accept)
+	var best *encoder
+	bestQuality := 0.0
+
+	for i := range app.encoders {
+		quality := acceptQuality(ranges, app.encoders[i].mediaType)
+
+		if quality > bestQuality {
+			best = &app.encoders[i]
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// parseAccept parses the values of an Accept or Accept-Encoding header.
+func parseAccept(accept string) []acceptedValue {
+	values := make([]acceptedValue, 0, strings.Count(accept, ",")+1)
+
+	for _, part := range strings.Split(accept, ",") {
+		value, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		q, exists := params["q"]
+
+		if exists {
+			quality, err = strconv.ParseFloat(q, 64)
+
+			if err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+
+		values = append(values, acceptedValue{
+			value:   value,
+			quality: quality,
+		})
+	}
+
+	return values
+}
+
+// acceptQuality returns the quality of the most specific
+// media range that matches the media type.
+func acceptQuality(ranges []acceptedValue, mediaType string) float64 {
+	quality := 0.0
+	specificity := -1
+	slash := strings.IndexByte(mediaType, '/')
+
+	for _, accepted := range ranges {
+		current := -1
+
+		switch {
+		case accepted.value == mediaType:
+			current = 2
+		case accepted.value == mediaType[:slash+1]+"*":
+			current = 1
+		case accepted.value == "*/*":
+			current = 0
+		}
+
+		if current > specificity {
+			specificity = current
+			quality = accepted.quality
+		}
+	}
+
+	return quality
+}
+
+// encodeJSON encodes the value as JSON.
+func encodeJSON(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// encodeXML encodes the value as an XML document.
+func encodeXML(value interface{}) ([]byte, error) {
+	body, err := xml.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// encodeMessagePack encodes the value as MessagePack.
+// Struct fields without a `msgpack` tag use their `json` tag.
+func encodeMessagePack(value interface{}) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	encoder := msgpack.NewEncoder(&buffer)
+	encoder.SetCustomStructTag("json")
+	err := encoder.Encode(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// encodeCBOR encodes the value as CBOR.
+// Struct fields without a `cbor` tag use their `json` tag.
+func encodeCBOR(value interface{}) ([]byte, error) {
+	return cbor.Marshal(value)
+}
+
+// encodeCSV encodes a [][]string or a slice of structs as CSV.
+// Structs are written with a header row containing the `csv` tags
+// or the names of the exported fields.
+func encodeCSV(value interface{}) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	writer := csv.NewWriter(&buffer)
+	records, isRecords := value.([][]string)
+
+	if isRecords {
+		err := writer.WriteAll(records)
+		return buffer.Bytes(), err
+	}
+
+	slice := reflect.ValueOf(value)
+
+	if slice.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w %T", errUnsupportedType, value)
+	}
+
+	structType := slice.Type().Elem()
+
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w %T", errUnsupportedType, value)
+	}
+
+	var columns []int
+	var header []string
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := field.Tag.Get("csv")
+
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, i)
+		header = append(header, name)
+	}
+
+	err := writer.Write(header)
+
+	if err != nil {
+		return nil, err
+	}
+
+	record := make([]string, len(columns))
+
+	for row := 0; row < slice.Len(); row++ {
+		element := reflect.Indirect(slice.Index(row))
+
+		for i, column := range columns {
+			record[i] = ""
+
+			if element.IsValid() {
+				record[i] = fmt.Sprint(element.Field(column).Interface())
+			}
+		}
+
+		err = writer.Write(record)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
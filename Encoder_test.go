@@ -0,0 +1,88 @@
+package aero_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+type book struct {
+	Title  string `json:"title" xml:"title" csv:"title"`
+	Pages  int    `json:"pages" xml:"pages" csv:"pages"`
+	secret string
+}
+
+func TestNegotiate(t *testing.T) {
+	app := aero.New()
+
+	app.RegisterEncoder("application/x-test", func(value interface{}) ([]byte, error) {
+		return []byte("test"), nil
+	})
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Negotiate([]book{{"Go", 380, ""}, {"Aero", 42, ""}})
+	})
+
+	tests := []struct {
+		accept      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"", http.StatusOK, "application/json", `[{"title":"Go","pages":380},{"title":"Aero","pages":42}]`},
+		{"*/*", http.StatusOK, "application/json", `[{"title":"Go","pages":380},{"title":"Aero","pages":42}]`},
+		{"text/csv", http.StatusOK, "text/csv", "title,pages\nGo,380\nAero,42\n"},
+		{"text/*, application/json;q=0.9", http.StatusOK, "text/csv", "title,pages\n"},
+		{"application/xml;q=0.5, application/json;q=0.4", http.StatusOK, "application/xml", "<book><title>Go</title>"},
+		{"application/*;q=0.8, application/json;q=0, text/csv;q=0.1", http.StatusOK, "application/xml", "<?xml"},
+		{"application/x-test, */*;q=0.1", http.StatusOK, "application/x-test", "test"},
+		{"application/msgpack", http.StatusOK, "application/msgpack", "\x92\x82\xa5title\xa2Go\xa5pages\xcd\x01\x7c"},
+		{"application/cbor", http.StatusOK, "application/cbor", "\x82\xa2\x65title\x62Go\x65pages\x19\x01\x7c"},
+		{"image/png", http.StatusNotAcceptable, "", ""},
+		{"*/*;q=0", http.StatusNotAcceptable, "", ""},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		if test.accept != "" {
+			request.Header.Set("Accept", test.accept)
+		}
+
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, test.code)
+		assert.Equal(t, response.Header().Get("Vary"), "Accept")
+
+		if test.code != http.StatusOK {
+			continue
+		}
+
+		assert.True(t, strings.HasPrefix(response.Header().Get("Content-Type"), test.contentType))
+		assert.Contains(t, response.Body.String(), test.body)
+	}
+}
+
+func TestNegotiateErrors(t *testing.T) {
+	app := aero.New()
+
+	app.Get("/", func(ctx aero.Context) error {
+		err := ctx.Negotiate(map[string]int{"a": 1})
+		assert.NotNil(t, err)
+		return err
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "text/csv")
+	app.ServeHTTP(httptest.NewRecorder(), request)
+
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+
+	app.RegisterEncoder("invalid/", nil)
+}
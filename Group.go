@@ -0,0 +1,149 @@
+package aero
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Group is a set of routes sharing a common path prefix and middleware.
+type Group struct {
+	app        *Application
+	host       *Host
+	prefix     string
+	middleware []Middleware
+}
+
+// Group creates a new route group with the given path prefix.
+// The middleware is only applied to routes registered within the group.
+func (app *Application) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		app:        app,
+		host:       &app.host,
+		prefix:     groupPrefix(prefix),
+		middleware: middleware,
+	}
+}
+
+// Group creates a nested route group that inherits
+// the prefix and middleware of the parent group.
+func (group *Group) Group(prefix string, middleware ...Middleware) *Group {
+	combined := make([]Middleware, 0, len(group.middleware)+len(middleware))
+	combined = append(combined, group.middleware...)
+	combined = append(combined, middleware...)
+
+	return &Group{
+		app:        group.app,
+		host:       group.host,
+		prefix:     group.prefix + groupPrefix(prefix),
+		middleware: combined,
+	}
+}
+
+// Use adds middleware to the group's middleware chain.
+// It only affects routes that are registered after the call.
+func (group *Group) Use(middleware ...Middleware) {
+	group.middleware = append(group.middleware, middleware...)
+}
+
+// Get registers your function to be called when the given GET path has been requested.
+func (group *Group) Get(path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(http.MethodGet, path, handler, middleware)
+}
+
+// Post registers your function to be called when the given POST path has been requested.
+func (group *Group) Post(path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(http.MethodPost, path, handler, middleware)
+}
+
+// Delete registers your function to be called when the given DELETE path has been requested.
+func (group *Group) Delete(path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(http.MethodDelete, path, handler, middleware)
+}
+
+// Put registers your function to be called when the given PUT path has been requested.
+func (group *Group) Put(path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(http.MethodPut, path, handler, middleware)
+}
+
+// Patch registers your function to be called when the given PATCH path has been requested.
+func (group *Group) Patch(path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(http.MethodPatch, path, handler, middleware)
+}
+
+// Head registers your function to be called when the given HEAD path has been requested.
+func (group *Group) Head(path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(http.MethodHead, path, handler, middleware)
+}
+
+// Options registers your function to be called when the given OPTIONS path has been requested.
+func (group *Group) Options(path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(http.MethodOptions, path, handler, middleware)
+}
+
+// Handle registers your function to be called when the given path has been requested
+// with the given method.
+func (group *Group) Handle(method string, path string, handler Handler, middleware ...Middleware) *Route {
+	return group.add(method, path, handler, middleware)
+}
+
+// Any registers your function to be called with any http method.
+// Non-standard methods like PROPFIND are included unless they have a route of their own.
+func (group *Group) Any(path string, handler Handler, middleware ...Middleware) {
+	for _, method := range append(methods[:], anyMethod) {
+		group.add(method, path, handler, middleware)
+	}
+}
+
+// Mount registers an http.Handler, e.g. another application,
+// for all requests whose path starts with the given prefix.
+// Besides the standard methods, the handler also receives
+// non-standard methods like PROPFIND that have no route of their own.
+// The prefix is stripped from the request path before the
+// handler is called and the group middleware is applied.
+func (group *Group) Mount(prefix string, handler http.Handler) {
+	mounted, isApplication := handler.(*Application)
+
+	if isApplication {
+		group.app.mounted = append(group.app.mounted, mounted)
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	wrapped := mount(handler)
+
+	for _, method := range append(methods[:], anyMethod) {
+		group.add(method, prefix+"/*"+mountParameter, wrapped, nil)
+
+		if prefix != "" {
+			group.add(method, prefix, wrapped, nil)
+		}
+	}
+}
+
+// add registers the handler with the group middleware
+// and the route-specific middleware bound to it.
+func (group *Group) add(method string, path string, handler Handler, middleware []Middleware) *Route {
+	combined := make([]Middleware, 0, len(group.middleware)+len(middleware))
+	combined = append(combined, group.middleware...)
+	combined = append(combined, middleware...)
+	return group.host.Router().add(method, group.path(path), handler, combined)
+}
+
+// path returns the full path for a route inside the group.
+func (group *Group) path(path string) string {
+	if path == "" || (path == "/" && group.prefix != "") {
+		return group.prefix
+	}
+
+	return group.prefix + path
+}
+
+// groupPrefix returns the prefix with a leading slash and without a trailing slash.
+func groupPrefix(prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	if prefix != "" && prefix[0] != separator {
+		prefix = "/" + prefix
+	}
+
+	return prefix
+}
@@ -0,0 +1,120 @@
+package aero_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+func TestGroup(t *testing.T) {
+	app := aero.New()
+	api := app.Group("/api/v1")
+
+	api.Get("/users", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	api.Get("/users/:id", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("id"))
+	})
+
+	api.Get("/", func(ctx aero.Context) error {
+		return ctx.Text("index")
+	})
+
+	response := test(app, "/api/v1/users")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), helloWorld)
+
+	response = test(app, "/api/v1/users/42")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "42")
+
+	response = test(app, "/api/v1")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "index")
+
+	response = test(app, "/users")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
+
+func TestGroupPrefixWithoutSlash(t *testing.T) {
+	app := aero.New()
+	api := app.Group("api/")
+	v1 := api.Group("v1")
+
+	api.Get("/users", func(ctx aero.Context) error {
+		return ctx.Text("users")
+	})
+
+	v1.Get("/status", func(ctx aero.Context) error {
+		return ctx.Text("status")
+	})
+
+	response := test(app, "/api/users")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "users")
+
+	response = test(app, "/api/v1/status")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "status")
+}
+
+func TestGroupMiddleware(t *testing.T) {
+	app := aero.New()
+
+	header := func(key string, value string) aero.Middleware {
+		return func(next aero.Handler) aero.Handler {
+			return func(ctx aero.Context) error {
+				ctx.Response().SetHeader(key, ctx.Response().Header(key)+value)
+				return next(ctx)
+			}
+		}
+	}
+
+	app.Use(header("X-Chain", "app,"))
+	admin := app.Group("/admin", header("X-Chain", "admin,"))
+	users := admin.Group("/users", header("X-Chain", "users,"))
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	admin.Get("/dashboard", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	users.Get("/:id", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("id"))
+	})
+
+	app.BindMiddleware()
+
+	response := test(app, "/")
+	assert.Equal(t, response.Header().Get("X-Chain"), "app,")
+
+	response = test(app, "/admin/dashboard")
+	assert.Equal(t, response.Header().Get("X-Chain"), "app,admin,")
+
+	response = test(app, "/admin/users/42")
+	assert.Equal(t, response.Header().Get("X-Chain"), "app,admin,users,")
+	assert.Equal(t, response.Body.String(), "42")
+}
+
+func TestGroupMethods(t *testing.T) {
+	app := aero.New()
+	group := app.Group("/group/")
+	handler := func(ctx aero.Context) error { return nil }
+
+	group.Get("/", handler)
+	group.Post("/", handler)
+	group.Put("/", handler)
+	group.Delete("/", handler)
+	group.Patch("/", handler)
+
+	for _, method := range []string{"GET", "POST", "PUT", "DELETE", "PATCH"} {
+		assert.NotNil(t, app.Router().Find(method, "/group"))
+	}
+}
@@ -1,6 +1,14 @@
 package aero
 
-import "net/http/httptest"
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+// mountParameter is the name of the wildcard parameter
+// that contains the path below a mounted handler.
+const mountParameter = "mounted"
 
 // Handler is a function that deals with the given request/response context.
 type Handler func(Context) error
@@ -34,3 +42,25 @@ func (handler Handler) Simulate(ctx Context) (*httptest.ResponseRecorder, error)
 
 	return response, err
 }
+
+// emptyResponse responds with the status of the context and an empty body.
+func emptyResponse(ctx Context) error {
+	ctx.Response().Internal().WriteHeader(ctx.Status())
+	return nil
+}
+
+// mount returns a handler that calls the http.Handler with the path
+// of the request relative to the mount point.
+func mount(handler http.Handler) Handler {
+	return func(ctx Context) error {
+		request := ctx.Request().Internal()
+		inner := new(http.Request)
+		*inner = *request
+		inner.URL = new(url.URL)
+		*inner.URL = *request.URL
+		inner.URL.Path = "/" + ctx.Get(mountParameter)
+		inner.URL.RawPath = ""
+		handler.ServeHTTP(ctx.Response().Internal(), inner)
+		return nil
+	}
+}
@@ -3,8 +3,12 @@ package aero
 // This list includes all the common header keys
 // and values used in the http server code.
 const (
+	acceptHeader                  = "Accept"
+	acceptRangesHeader            = "Accept-Ranges"
+	allowHeader                   = "Allow"
 	cacheControlHeader            = "Cache-Control"
 	cacheControlAlwaysValidate    = "must-revalidate"
+	cacheControlImmutable         = "public, max-age=31536000, immutable"
 	cacheControlMedia             = "public, max-age=13824000"
 	cacheControlNoCache           = "no-cache"
 	connectionHeader              = "Connection"
@@ -21,14 +25,29 @@ const (
 	contentTypeCSS                = "text/css; charset=utf-8"
 	contentTypeJavaScript         = "text/javascript; charset=utf-8"
 	contentTypeJSON               = "application/json; charset=utf-8"
+	contentTypeXML                = "application/xml; charset=utf-8"
+	contentTypeCSV                = "text/csv; charset=utf-8"
+	contentTypeMessagePack        = "application/msgpack"
+	contentTypeCBOR               = "application/cbor"
 	contentTypePlainText          = "text/plain; charset=utf-8"
 	contentTypeEventStream        = "text/event-stream; charset=utf-8"
 	contentTypeSVG                = "image/svg+xml"
 	contentEncodingHeader         = "Content-Encoding"
 	contentEncodingGzip           = "gzip"
+	contentEncodingDeflate        = "deflate"
+	contentEncodingBrotli         = "br"
+	contentEncodingZstd           = "zstd"
 	acceptEncodingHeader          = "Accept-Encoding"
 	contentLengthHeader           = "Content-Length"
+	contentRangeHeader            = "Content-Range"
+	ifMatchHeader                 = "If-Match"
+	ifModifiedSinceHeader         = "If-Modified-Since"
 	ifNoneMatchHeader             = "If-None-Match"
+	ifRangeHeader                 = "If-Range"
+	ifUnmodifiedSinceHeader       = "If-Unmodified-Since"
+	lastModifiedHeader            = "Last-Modified"
+	locationHeader                = "Location"
+	rangeHeader                   = "Range"
 	referrerPolicyHeader          = "Referrer-Policy"
 	referrerPolicySameOrigin      = "no-referrer"
 	strictTransportSecurityHeader = "Strict-Transport-Security"
@@ -36,4 +55,5 @@ const (
 	contentSecurityPolicyHeader   = "Content-Security-Policy"
 	forwardedForHeader            = "X-Forwarded-For"
 	realIPHeader                  = "X-Real-Ip"
+	varyHeader                    = "Vary"
 )
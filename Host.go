@@ -0,0 +1,196 @@
+package aero
+
+import (
+	"net"
+	"strings"
+	"sync/atomic"
+)
+
+// Host is a virtual host with its own routes, middleware and error handlers.
+// The pattern can contain parameters like :tenant.example.com and
+// wildcards like *.example.com that each match a single label.
+type Host struct {
+	*Group
+	pattern          string
+	labels           []string
+	router           atomic.Value
+	notFound         Handler
+	methodNotAllowed Handler
+	options          Handler
+	bound            errorHandlers
+}
+
+// errorHandlers are the handlers for requests without a matching route.
+type errorHandlers struct {
+	notFound         Handler
+	methodNotAllowed Handler
+	options          Handler
+}
+
+// Host returns the virtual host for the given pattern.
+// Requests for a host that doesn't match any pattern
+// are handled by the routes registered on the application.
+func (app *Application) Host(pattern string) *Host {
+	pattern = strings.ToLower(pattern)
+
+	for _, host := range app.hosts {
+		if host.pattern == pattern {
+			return host
+		}
+	}
+
+	host := &Host{
+		pattern: pattern,
+		labels:  strings.Split(pattern, "."),
+	}
+
+	host.Group = &Group{app: app, host: host}
+	host.router.Store(&Router{})
+	app.hosts = append(app.hosts, host)
+	return host
+}
+
+// NotFound registers the handler that is called when no route of the host matches the request path.
+func (host *Host) NotFound(handler Handler) {
+	host.notFound = handler
+}
+
+// MethodNotAllowed registers the handler that is called when the request path
+// is only registered for other methods of the host.
+func (host *Host) MethodNotAllowed(handler Handler) {
+	host.methodNotAllowed = handler
+}
+
+// Pattern returns the host pattern, e.g. *.example.com.
+func (host *Host) Pattern() string {
+	return host.pattern
+}
+
+// Router returns the router that currently serves the requests of the host.
+func (host *Host) Router() *Router {
+	return host.router.Load().(*Router)
+}
+
+// SetRouter binds the application middleware to the router and atomically
+// replaces the router of the host. Requests that are in flight finish
+// with the previous router, new requests use the given router.
+// The router must not be modified after it has been set.
+func (host *Host) SetRouter(router *Router) {
+	middleware := host.app.middleware
+
+	router.bind(func(handler Handler) Handler {
+		return handler.Bind(middleware...)
+	})
+
+	host.router.Store(router)
+}
+
+// URL returns the path of the host route with the given name and parameters.
+func (host *Host) URL(name string, params ...string) (string, error) {
+	return host.Router().URL(name, params...)
+}
+
+// fallback returns the handler of the host if it has been set
+// and the handler of the application otherwise.
+func fallback(handler Handler, application Handler) Handler {
+	if handler != nil {
+		return handler
+	}
+
+	return application
+}
+
+// bind applies the middleware to the routes and error handlers of the host.
+// The error handlers additionally run through the host middleware.
+// Binding always starts from the registered handlers, so binding
+// again doesn't run the middleware multiple times.
+func (host *Host) bind(middleware []Middleware) {
+	host.Router().bind(func(handler Handler) Handler {
+		return handler.Bind(middleware...)
+	})
+
+	chain := append(middleware[:len(middleware):len(middleware)], host.Group.middleware...)
+	registered := host.registered()
+
+	host.bound = errorHandlers{
+		notFound:         registered.notFound.Bind(chain...),
+		methodNotAllowed: registered.methodNotAllowed.Bind(chain...),
+		options:          registered.options.Bind(chain...),
+	}
+}
+
+// errorHandlers returns the error handlers bound to the middleware
+// or the registered ones if the middleware hasn't been bound yet.
+func (host *Host) errorHandlers() errorHandlers {
+	if host.bound.notFound != nil {
+		return host.bound
+	}
+
+	return host.registered()
+}
+
+// registered returns the error handlers registered for the host,
+// falling back to the ones registered for the application.
+func (host *Host) registered() errorHandlers {
+	app := &host.app.host
+
+	return errorHandlers{
+		notFound:         fallback(host.notFound, app.notFound),
+		methodNotAllowed: fallback(host.methodNotAllowed, app.methodNotAllowed),
+		options:          fallback(host.options, app.options),
+	}
+}
+
+// match reports whether the host name matches the pattern
+// and adds the host parameters to the context.
+func (host *Host) match(name string, ctx *context) bool {
+	if host.pattern == name {
+		return true
+	}
+
+	count := 0
+
+	for index, label := range host.labels {
+		var value string
+
+		if index == len(host.labels)-1 {
+			value = name
+		} else {
+			end := strings.IndexByte(name, '.')
+
+			if end == -1 {
+				ctx.paramCount -= count
+				return false
+			}
+
+			value = name[:end]
+			name = name[end+1:]
+		}
+
+		switch {
+		case label == "*" && value != "":
+			continue
+
+		case len(label) > 1 && label[0] == parameter && value != "":
+			ctx.addParameter(label[1:], value)
+			count++
+
+		case label != value:
+			ctx.paramCount -= count
+			return false
+		}
+	}
+
+	return true
+}
+
+// hostName returns the lowercase host name without the port.
+func hostName(host string) string {
+	name, _, err := net.SplitHostPort(host)
+
+	if err == nil {
+		host = name
+	}
+
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
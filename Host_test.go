@@ -0,0 +1,175 @@
+package aero_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+func TestHost(t *testing.T) {
+	app := aero.New()
+	api := app.Host("api.example.com")
+	tenant := app.Host(":tenant.example.com")
+	wildcard := app.Host("*.example.org")
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text("default")
+	})
+
+	api.Get("/", func(ctx aero.Context) error {
+		return ctx.Text("api")
+	})
+
+	tenant.Get("/users/:id", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("tenant") + ":" + ctx.Get("id"))
+	})
+
+	wildcard.Get("/", func(ctx aero.Context) error {
+		return ctx.Text("wildcard")
+	})
+
+	tests := []struct {
+		host string
+		path string
+		code int
+		body string
+	}{
+		{"example.com", "/", http.StatusOK, "default"},
+		{"api.example.com", "/", http.StatusOK, "api"},
+		{"API.example.com:4000", "/", http.StatusOK, "api"},
+		{"acme.example.com", "/users/42", http.StatusOK, "acme:42"},
+		{"acme.example.com", "/", http.StatusNotFound, ""},
+		{"a.b.example.com", "/", http.StatusOK, "default"},
+		{"www.example.org", "/", http.StatusOK, "wildcard"},
+		{"example.org", "/", http.StatusOK, "default"},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, test.path, nil)
+		request.Host = test.host
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+
+		assert.Equal(t, response.Code, test.code)
+		assert.Equal(t, response.Body.String(), test.body)
+	}
+}
+
+func TestHostMiddleware(t *testing.T) {
+	app := aero.New()
+	admin := app.Host("admin.example.com")
+
+	app.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			ctx.Response().SetHeader("X-App", "true")
+			return next(ctx)
+		}
+	})
+
+	admin.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			ctx.Response().SetHeader("X-Admin", "true")
+			return next(ctx)
+		}
+	})
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	admin.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	admin.NotFound(func(ctx aero.Context) error {
+		return ctx.Text("admin page not found")
+	})
+
+	app.BindMiddleware()
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Host = "admin.example.com"
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Header().Get("X-App"), "true")
+	assert.Equal(t, response.Header().Get("X-Admin"), "true")
+
+	request = httptest.NewRequest(http.MethodGet, "/404", nil)
+	request.Host = "admin.example.com"
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusNotFound)
+	assert.Equal(t, response.Body.String(), "admin page not found")
+	assert.Equal(t, response.Header().Get("X-Admin"), "true")
+
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Host = "example.com"
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Header().Get("X-App"), "true")
+	assert.Equal(t, response.Header().Get("X-Admin"), "")
+}
+
+func TestHostBindMiddlewareTwice(t *testing.T) {
+	app := aero.New()
+	admin := app.Host("admin.example.com")
+	count := 0
+
+	app.Use(func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			count++
+			return next(ctx)
+		}
+	})
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	admin.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	app.BindMiddleware()
+	app.BindMiddleware()
+
+	for _, host := range []string{"example.com", "admin.example.com"} {
+		for _, path := range []string{"/", "/404"} {
+			count = 0
+			request := httptest.NewRequest(http.MethodGet, path, nil)
+			request.Host = host
+			response := httptest.NewRecorder()
+			app.ServeHTTP(response, request)
+			assert.Equal(t, count, 1)
+		}
+	}
+}
+
+func TestHostURLFor(t *testing.T) {
+	app := aero.New()
+	api := app.Host("api.example.com")
+
+	api.Get("/user/:id", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("id"))
+	}).Name("user")
+
+	api.Get("/", func(ctx aero.Context) error {
+		url, err := ctx.URLFor("user", "id", "1")
+
+		if err != nil {
+			return err
+		}
+
+		return ctx.Text(url)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Host = "api.example.com"
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "/user/1")
+}
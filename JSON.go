@@ -0,0 +1,265 @@
+package aero
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONOptions configures how JSON bodies are decoded.
+type JSONOptions struct {
+	// DisallowUnknownFields fails the decoding if an object
+	// has keys that don't match any field of the struct.
+	DisallowUnknownFields bool
+
+	// UseNumber decodes numbers in interface{} values
+	// as json.Number instead of float64.
+	UseNumber bool
+
+	// MaxDepth limits the nesting of objects and arrays.
+	// A depth of 0 means that there is no limit.
+	MaxDepth int
+}
+
+// DecodeJSON decodes a JSON body directly into a value of type T
+// without building a generic interface{} tree first.
+func DecodeJSON[T any](body RequestBody, options ...JSONOptions) (T, error) {
+	var value T
+
+	if body.reader == nil {
+		return value, ErrEmptyBody
+	}
+
+	defer body.reader.Close()
+	decoder := newJSONDecoder(body.reader, options)
+	err := decoder.decode(&value)
+
+	if err == io.EOF {
+		return value, ErrEmptyBody
+	}
+
+	return value, err
+}
+
+// JSONStream decodes the items of a JSON array or of newline
+// delimited JSON (NDJSON) one by one, so that large bodies
+// never need to be held in memory at once.
+type JSONStream[T any] struct {
+	body    io.ReadCloser
+	reader  *bufio.Reader
+	decoder *jsonDecoder
+	options []JSONOptions
+	array   bool
+	err     error
+}
+
+// StreamJSON returns a stream of the items in the body. If the body
+// starts with [ the elements of the array are returned, otherwise
+// the body is treated as a sequence of JSON values.
+func StreamJSON[T any](body RequestBody, options ...JSONOptions) *JSONStream[T] {
+	stream := &JSONStream[T]{
+		body:    body.reader,
+		options: options,
+	}
+
+	if body.reader == nil {
+		stream.err = ErrEmptyBody
+	}
+
+	return stream
+}
+
+// Next decodes the next item and returns io.EOF
+// when there are no more items in the stream.
+func (stream *JSONStream[T]) Next() (T, error) {
+	var item T
+
+	if stream.err != nil {
+		return item, stream.err
+	}
+
+	if stream.decoder == nil {
+		stream.err = stream.start()
+
+		if stream.err != nil {
+			return item, stream.fail(stream.err)
+		}
+	}
+
+	if stream.array && !stream.decoder.More() {
+		_, err := stream.decoder.token()
+
+		if err != nil {
+			return item, stream.fail(err)
+		}
+
+		return item, stream.fail(io.EOF)
+	}
+
+	err := stream.decoder.decode(&item)
+
+	if err != nil {
+		return item, stream.fail(err)
+	}
+
+	return item, nil
+}
+
+// start detects the format of the body and consumes the opening bracket of arrays.
+func (stream *JSONStream[T]) start() error {
+	stream.reader = bufio.NewReader(stream.body)
+
+	for {
+		char, err := stream.reader.ReadByte()
+
+		if err != nil {
+			return err
+		}
+
+		if !isJSONSpace(char) {
+			stream.array = char == '['
+			_ = stream.reader.UnreadByte()
+			break
+		}
+	}
+
+	stream.decoder = newJSONDecoder(stream.reader, stream.options)
+
+	if stream.array {
+		// The maximum depth applies to the elements, not to the array around them.
+		if stream.decoder.depth != nil {
+			stream.decoder.depth.outer = 1
+		}
+
+		_, err := stream.decoder.token()
+		return err
+	}
+
+	return nil
+}
+
+// fail ends the stream with the given error and closes the body.
+func (stream *JSONStream[T]) fail(err error) error {
+	stream.err = err
+	_ = stream.body.Close()
+	return err
+}
+
+// jsonDecoder is a JSON decoder that reports
+// ErrJSONTooDeep if the maximum depth is exceeded.
+type jsonDecoder struct {
+	*json.Decoder
+	depth *depthReader
+}
+
+// newJSONDecoder creates a decoder for the reader with the given options.
+func newJSONDecoder(reader io.Reader, options []JSONOptions) *jsonDecoder {
+	var config JSONOptions
+
+	if len(options) > 0 {
+		config = options[0]
+	}
+
+	decoder := &jsonDecoder{}
+
+	if config.MaxDepth > 0 {
+		decoder.depth = &depthReader{
+			reader:   reader,
+			maxDepth: config.MaxDepth,
+		}
+
+		reader = decoder.depth
+	}
+
+	decoder.Decoder = json.NewDecoder(reader)
+
+	if config.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if config.UseNumber {
+		decoder.UseNumber()
+	}
+
+	return decoder
+}
+
+// decode decodes the next JSON value into the given value.
+func (decoder *jsonDecoder) decode(value interface{}) error {
+	return decoder.check(decoder.Decode(value))
+}
+
+// token returns the next JSON token.
+func (decoder *jsonDecoder) token() (json.Token, error) {
+	token, err := decoder.Token()
+	return token, decoder.check(err)
+}
+
+// check replaces decoding errors caused by exceeding the maximum depth,
+// because the decoder doesn't return errors of the reader unchanged.
+func (decoder *jsonDecoder) check(err error) error {
+	if err != nil && decoder.depth != nil && decoder.depth.err != nil {
+		return decoder.depth.err
+	}
+
+	return err
+}
+
+// depthReader fails with ErrJSONTooDeep as soon as the JSON passing
+// through it is nested deeper than the maximum depth.
+// The outer levels of nesting don't count towards the maximum.
+type depthReader struct {
+	reader   io.Reader
+	maxDepth int
+	outer    int
+	depth    int
+	inString bool
+	escaped  bool
+	err      error
+}
+
+// Read implements the io.Reader interface.
+func (reader *depthReader) Read(buffer []byte) (int, error) {
+	if reader.err != nil {
+		return 0, reader.err
+	}
+
+	n, err := reader.reader.Read(buffer)
+
+	for i, char := range buffer[:n] {
+		switch {
+		case reader.escaped:
+			reader.escaped = false
+
+		case reader.inString:
+			switch char {
+			case '\\':
+				reader.escaped = true
+			case '"':
+				reader.inString = false
+			}
+
+		case char == '"':
+			reader.inString = true
+
+		case char == '{' || char == '[':
+			reader.depth++
+
+			if reader.depth-reader.outer > reader.maxDepth {
+				reader.err = fmt.Errorf("%w: maximum is %d", ErrJSONTooDeep, reader.maxDepth)
+				return i, reader.err
+			}
+
+		case char == '}' || char == ']':
+			reader.depth--
+		}
+	}
+
+	return n, err
+}
+
+// isJSONSpace reports whether the character is JSON whitespace.
+func isJSONSpace(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
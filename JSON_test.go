@@ -0,0 +1,173 @@
+package aero_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+type item struct {
+	ID    int         `json:"id"`
+	Name  string      `json:"name"`
+	Extra interface{} `json:"extra"`
+}
+
+// decode sends the body to a handler and returns the error of the handler.
+func decode(t *testing.T, body string, handler func(aero.RequestBody) error) error {
+	app := aero.New()
+	var result error
+
+	app.Post("/", func(ctx aero.Context) error {
+		result = handler(ctx.Request().Body())
+		return nil
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	app.ServeHTTP(httptest.NewRecorder(), request)
+	return result
+}
+
+func TestDecodeJSON(t *testing.T) {
+	err := decode(t, `{"id": 1, "name": "Alice", "extra": 42}`, func(body aero.RequestBody) error {
+		value, err := aero.DecodeJSON[item](body)
+		assert.Equal(t, value.ID, 1)
+		assert.Equal(t, value.Name, "Alice")
+		assert.Equal(t, value.Extra, 42.0)
+		return err
+	})
+
+	assert.Nil(t, err)
+
+	err = decode(t, `{"id": 1, "extra": 42}`, func(body aero.RequestBody) error {
+		value, err := aero.DecodeJSON[item](body, aero.JSONOptions{UseNumber: true})
+		assert.Equal(t, value.Extra, json.Number("42"))
+		return err
+	})
+
+	assert.Nil(t, err)
+
+	err = decode(t, `{"id": 1, "unknown": true}`, func(body aero.RequestBody) error {
+		_, err := aero.DecodeJSON[item](body, aero.JSONOptions{DisallowUnknownFields: true})
+		return err
+	})
+
+	assert.NotNil(t, err)
+
+	err = decode(t, `{"extra": [[["[[[["]]]}`, func(body aero.RequestBody) error {
+		_, err := aero.DecodeJSON[item](body, aero.JSONOptions{MaxDepth: 4})
+		return err
+	})
+
+	assert.Nil(t, err)
+
+	err = decode(t, `{"extra": [[[[]]]]}`, func(body aero.RequestBody) error {
+		_, err := aero.DecodeJSON[item](body, aero.JSONOptions{MaxDepth: 4})
+		return err
+	})
+
+	assert.True(t, errors.Is(err, aero.ErrJSONTooDeep))
+
+	err = decode(t, "", func(body aero.RequestBody) error {
+		_, err := aero.DecodeJSON[map[string]int](body)
+		return err
+	})
+
+	assert.Equal(t, err, aero.ErrEmptyBody)
+}
+
+func TestStreamJSON(t *testing.T) {
+	bodies := []string{
+		` [{"id": 1}, {"id": 2}, {"id": 3}] `,
+		"{\"id\": 1}\n{\"id\": 2}\n{\"id\": 3}\n",
+	}
+
+	for _, body := range bodies {
+		var ids []int
+
+		err := decode(t, body, func(body aero.RequestBody) error {
+			stream := aero.StreamJSON[item](body)
+
+			for {
+				value, err := stream.Next()
+
+				if err == io.EOF {
+					return nil
+				}
+
+				if err != nil {
+					return err
+				}
+
+				ids = append(ids, value.ID)
+			}
+		})
+
+		assert.Nil(t, err)
+		assert.DeepEqual(t, ids, []int{1, 2, 3})
+	}
+
+	for _, body := range []string{"", " [] "} {
+		err := decode(t, body, func(body aero.RequestBody) error {
+			_, err := aero.StreamJSON[item](body).Next()
+			return err
+		})
+
+		assert.Equal(t, err, io.EOF)
+	}
+
+	err := decode(t, `[{"id": 1}, {"id": "2"}]`, func(body aero.RequestBody) error {
+		stream := aero.StreamJSON[item](body)
+		_, err := stream.Next()
+		assert.Nil(t, err)
+		_, err = stream.Next()
+		assert.NotNil(t, err)
+		_, again := stream.Next()
+		assert.Equal(t, again, err)
+		return err
+	})
+
+	assert.NotNil(t, err)
+}
+
+func TestStreamJSONMaxDepth(t *testing.T) {
+	tests := []struct {
+		body    string
+		tooDeep bool
+	}{
+		{`[{"id": 1}, {"id": 2}]`, false},
+		{"{\"id\": 1}\n{\"id\": 2}\n", false},
+		{`[{"id": 1}, {"extra": {}}]`, true},
+		{"{\"id\": 1}\n{\"extra\": []}\n", true},
+	}
+
+	for _, test := range tests {
+		err := decode(t, test.body, func(body aero.RequestBody) error {
+			stream := aero.StreamJSON[item](body, aero.JSONOptions{MaxDepth: 1})
+
+			for {
+				_, err := stream.Next()
+
+				if err == io.EOF {
+					return nil
+				}
+
+				if err != nil {
+					return err
+				}
+			}
+		})
+
+		assert.Equal(t, errors.Is(err, aero.ErrJSONTooDeep), test.tooDeep)
+
+		if !test.tooDeep {
+			assert.Nil(t, err)
+		}
+	}
+}
@@ -1,5 +1,46 @@
 package aero
 
+import (
+	stdContext "context"
+	"net/http"
+)
+
 // Middleware is a function that accepts a handler
 // and transforms it into a different handler.
 type Middleware func(Handler) Handler
+
+// wrappedState is passed through the request context of a wrapped
+// net/http middleware so that the next handler can be called.
+type wrappedState struct {
+	ctx Context
+	err error
+}
+
+// wrappedStateKey is the request context key for the wrapped state.
+type wrappedStateKey struct{}
+
+// WrapMiddleware converts a net/http middleware into a Middleware.
+// Changes of the request or response writer made by the net/http
+// middleware are visible to the following handlers.
+func WrapMiddleware(wrap func(http.Handler) http.Handler) Middleware {
+	return func(next Handler) Handler {
+		handler := wrap(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			state := request.Context().Value(wrappedStateKey{}).(*wrappedState)
+			state.ctx.Request().SetInternal(request)
+			state.ctx.Response().SetInternal(response)
+			state.err = next(state.ctx)
+		}))
+
+		return func(ctx Context) error {
+			request := ctx.Request().Internal()
+			response := ctx.Response().Internal()
+			state := &wrappedState{ctx: ctx}
+			handler.ServeHTTP(response, request.WithContext(stdContext.WithValue(request.Context(), wrappedStateKey{}, state)))
+
+			// Restore the original request and response
+			ctx.Request().SetInternal(request)
+			ctx.Response().SetInternal(response)
+			return state.err
+		}
+	}
+}
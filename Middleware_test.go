@@ -1,6 +1,7 @@
 package aero_test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -53,3 +54,39 @@ func TestApplicationMiddlewareSkipNext(t *testing.T) {
 	response := test(app, "/")
 	assert.Equal(t, response.Body.String(), "")
 }
+
+func TestWrapMiddleware(t *testing.T) {
+	app := aero.New()
+	type key struct{}
+
+	app.Get("/", func(ctx aero.Context) error {
+		value := ctx.Request().Context().Value(key{}).(string)
+		return ctx.Text(value)
+	})
+
+	app.Get("/denied", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	app.Use(aero.WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			if request.URL.Path == "/denied" {
+				http.Error(response, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			response.Header().Set("X-Standard", "true")
+			next.ServeHTTP(response, request.WithContext(context.WithValue(request.Context(), key{}, helloWorld)))
+		})
+	}))
+
+	app.BindMiddleware()
+
+	response := test(app, "/")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), helloWorld)
+	assert.Equal(t, response.Header().Get("X-Standard"), "true")
+
+	response = test(app, "/denied")
+	assert.Equal(t, response.Code, http.StatusForbidden)
+}
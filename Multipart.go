@@ -0,0 +1,272 @@
+package aero
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+)
+
+const (
+	// defaultMultipartMemory is the number of bytes of a part
+	// that Store keeps in memory if no limit has been configured.
+	defaultMultipartMemory = 10 << 20
+
+	// sniffLength is the number of bytes used to detect the content type.
+	sniffLength = 512
+)
+
+// MultipartOptions configures how multipart bodies are read.
+// Size limits of 0 mean that there is no limit.
+type MultipartOptions struct {
+	// MaxPartSize limits the size of each part.
+	MaxPartSize int64
+
+	// MaxTotalSize limits the size of the entire body.
+	MaxTotalSize int64
+
+	// MaxMemory is the number of bytes of a part that Store keeps
+	// in memory before it spills the part to a temporary file.
+	// It defaults to 10 MB.
+	MaxMemory int64
+
+	// TempDir is the directory for temporary files.
+	// It defaults to the directory returned by os.TempDir.
+	TempDir string
+}
+
+// MultipartReader iterates over the parts of a multipart body.
+type MultipartReader struct {
+	reader  *multipart.Reader
+	options MultipartOptions
+}
+
+// Part is a single field or file of a multipart body.
+// The contents are streamed from the request and
+// can only be read once.
+type Part struct {
+	part    *multipart.Part
+	reader  *bufio.Reader
+	options MultipartOptions
+}
+
+// StoredPart is a part that has been read completely,
+// either into memory or into a temporary file.
+// Close must be called to remove the temporary file.
+type StoredPart struct {
+	*io.SectionReader
+	file *os.File
+}
+
+// Next returns the next part or io.EOF if there are no more parts.
+// The previous part is skipped if it hasn't been read completely.
+func (reader *MultipartReader) Next() (*Part, error) {
+	part, err := reader.reader.NextPart()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var partReader io.Reader = part
+
+	if reader.options.MaxPartSize > 0 {
+		partReader = &limitedReader{
+			reader:    part,
+			remaining: reader.options.MaxPartSize,
+			err:       ErrPartTooLarge,
+		}
+	}
+
+	return &Part{
+		part:    part,
+		reader:  bufio.NewReaderSize(partReader, sniffLength),
+		options: reader.options,
+	}, nil
+}
+
+// FormName returns the name of the form field.
+func (part *Part) FormName() string {
+	return part.part.FormName()
+}
+
+// FileName returns the name of the uploaded file or an empty string
+// if the part is a regular form field. The name is stripped of any
+// directories and can be used as a file name, although it should
+// never be trusted.
+func (part *Part) FileName() string {
+	return part.part.FileName()
+}
+
+// IsFile reports whether the part is an uploaded file.
+func (part *Part) IsFile() bool {
+	return part.part.FileName() != ""
+}
+
+// Header returns the MIME header of the part.
+func (part *Part) Header() textproto.MIMEHeader {
+	return part.part.Header
+}
+
+// ContentType returns the content type declared by the client.
+func (part *Part) ContentType() string {
+	return part.part.Header.Get(contentTypeHeader)
+}
+
+// DetectContentType returns the content type detected from the first
+// bytes of the part. Unlike ContentType, it doesn't rely on the client
+// and should be used to check the type of uploaded files.
+func (part *Part) DetectContentType() (string, error) {
+	start, err := part.reader.Peek(sniffLength)
+
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+
+	return http.DetectContentType(start), nil
+}
+
+// Read reads from the contents of the part.
+func (part *Part) Read(buffer []byte) (int, error) {
+	return part.reader.Read(buffer)
+}
+
+// WriteTo streams the contents of the part to the writer.
+func (part *Part) WriteTo(writer io.Writer) (int64, error) {
+	return part.reader.WriteTo(writer)
+}
+
+// Bytes returns the contents of the part.
+func (part *Part) Bytes() ([]byte, error) {
+	return ioutil.ReadAll(part.reader)
+}
+
+// String returns the contents of the part as a string.
+func (part *Part) String() (string, error) {
+	data, err := part.Bytes()
+	return string(data), err
+}
+
+// SaveTo streams the contents of the part to the file at the given path.
+// The file is removed if the part couldn't be read completely.
+func (part *Part) SaveTo(path string) (int64, error) {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := part.WriteTo(file)
+	closeErr := file.Close()
+
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(path)
+		return size, err
+	}
+
+	return size, nil
+}
+
+// Store reads the part completely. Small parts are kept in memory,
+// parts larger than MaxMemory are spilled to a temporary file.
+func (part *Part) Store() (*StoredPart, error) {
+	maxMemory := part.options.MaxMemory
+
+	if maxMemory <= 0 {
+		maxMemory = defaultMultipartMemory
+	}
+
+	buffer := bytes.Buffer{}
+	size, err := io.CopyN(&buffer, part.reader, maxMemory+1)
+
+	if err == io.EOF {
+		return &StoredPart{
+			SectionReader: io.NewSectionReader(bytes.NewReader(buffer.Bytes()), 0, size),
+		}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := ioutil.TempFile(part.options.TempDir, "aero-upload-")
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &StoredPart{file: file}
+	_, err = buffer.WriteTo(file)
+
+	if err == nil {
+		var remaining int64
+		remaining, err = part.reader.WriteTo(file)
+		size += remaining
+	}
+
+	if err != nil {
+		_ = stored.Close()
+		return nil, err
+	}
+
+	stored.SectionReader = io.NewSectionReader(file, 0, size)
+	return stored, nil
+}
+
+// InMemory reports whether the part is kept in memory.
+func (stored *StoredPart) InMemory() bool {
+	return stored.file == nil
+}
+
+// Close removes the temporary file of the part.
+func (stored *StoredPart) Close() error {
+	if stored.file == nil {
+		return nil
+	}
+
+	err := stored.file.Close()
+	removeErr := os.Remove(stored.file.Name())
+
+	if err == nil {
+		err = removeErr
+	}
+
+	return err
+}
+
+// limitedReader reads from the reader until the limit is exceeded
+// and then returns the given error instead of silently truncating.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	err       error
+}
+
+// Read implements the io.Reader interface.
+func (limited *limitedReader) Read(buffer []byte) (int, error) {
+	if limited.remaining < 0 {
+		return 0, limited.err
+	}
+
+	if int64(len(buffer)) > limited.remaining+1 {
+		buffer = buffer[:limited.remaining+1]
+	}
+
+	n, err := limited.reader.Read(buffer)
+
+	if int64(n) <= limited.remaining {
+		limited.remaining -= int64(n)
+		return n, err
+	}
+
+	n = int(limited.remaining)
+	limited.remaining = -1
+	return n, limited.err
+}
@@ -0,0 +1,188 @@
+package aero_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+// multipartRequest creates a request with a text field and a file upload.
+func multipartRequest(t *testing.T, file []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	assert.Nil(t, writer.WriteField("title", "Avatar"))
+	part, err := writer.CreateFormFile("avatar", "../avatar.png")
+	assert.Nil(t, err)
+	_, err = part.Write(file)
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	request := httptest.NewRequest(http.MethodPost, "/", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestMultipart(t *testing.T) {
+	app := aero.New()
+	directory, err := ioutil.TempDir("", "aero-test-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(directory)
+
+	app.Post("/", func(ctx aero.Context) error {
+		reader, err := ctx.Request().Body().Multipart(aero.MultipartOptions{})
+
+		if err != nil {
+			return err
+		}
+
+		title, err := reader.Next()
+		assert.Nil(t, err)
+		assert.False(t, title.IsFile())
+		assert.Equal(t, title.FormName(), "title")
+		value, err := title.String()
+		assert.Nil(t, err)
+		assert.Equal(t, value, "Avatar")
+
+		avatar, err := reader.Next()
+		assert.Nil(t, err)
+		assert.True(t, avatar.IsFile())
+		assert.Equal(t, avatar.FileName(), "avatar.png")
+		assert.Equal(t, avatar.ContentType(), "application/octet-stream")
+		contentType, err := avatar.DetectContentType()
+		assert.Nil(t, err)
+		assert.Equal(t, contentType, "image/png")
+
+		size, err := avatar.SaveTo(filepath.Join(directory, avatar.FileName()))
+		assert.Nil(t, err)
+		assert.Equal(t, size, int64(len(pngHeader)+100))
+
+		_, err = reader.Next()
+		assert.Equal(t, err, io.EOF)
+		return ctx.Text("ok")
+	})
+
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, multipartRequest(t, append(pngHeader, make([]byte, 100)...)))
+	assert.Equal(t, response.Body.String(), "ok")
+
+	saved, err := ioutil.ReadFile(filepath.Join(directory, "avatar.png"))
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(saved, pngHeader))
+}
+
+func TestMultipartStore(t *testing.T) {
+	app := aero.New()
+	options := aero.MultipartOptions{MaxMemory: 64}
+
+	app.Post("/", func(ctx aero.Context) error {
+		reader, err := ctx.Request().Body().Multipart(options)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = reader.Next()
+		assert.Nil(t, err)
+		part, err := reader.Next()
+		assert.Nil(t, err)
+
+		stored, err := part.Store()
+		assert.Nil(t, err)
+		defer stored.Close()
+
+		data, err := ioutil.ReadAll(stored)
+		assert.Nil(t, err)
+
+		if stored.InMemory() {
+			return ctx.Text("memory " + string(data))
+		}
+
+		return ctx.Text("file " + string(data))
+	})
+
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, multipartRequest(t, []byte("small")))
+	assert.Equal(t, response.Body.String(), "memory small")
+
+	large := strings.Repeat("x", 100)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, multipartRequest(t, []byte(large)))
+	assert.Equal(t, response.Body.String(), "file "+large)
+}
+
+func TestMultipartLimits(t *testing.T) {
+	app := aero.New()
+	var options aero.MultipartOptions
+
+	app.Post("/", func(ctx aero.Context) error {
+		reader, err := ctx.Request().Body().Multipart(options)
+
+		if err != nil {
+			return err
+		}
+
+		for {
+			part, err := reader.Next()
+
+			if err == io.EOF {
+				return ctx.Text("ok")
+			}
+
+			if err == nil {
+				_, err = part.WriteTo(ioutil.Discard)
+			}
+
+			if errors.Is(err, aero.ErrPartTooLarge) || errors.Is(err, aero.ErrBodyTooLarge) {
+				return ctx.Error(http.StatusRequestEntityTooLarge, err)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	tests := []struct {
+		options aero.MultipartOptions
+		code    int
+	}{
+		{aero.MultipartOptions{}, http.StatusOK},
+		{aero.MultipartOptions{MaxPartSize: 1000}, http.StatusOK},
+		{aero.MultipartOptions{MaxPartSize: 99}, http.StatusRequestEntityTooLarge},
+		{aero.MultipartOptions{MaxTotalSize: 100}, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		options = test.options
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, multipartRequest(t, make([]byte, 100)))
+		assert.Equal(t, response.Code, test.code)
+	}
+}
+
+func TestMultipartInvalid(t *testing.T) {
+	app := aero.New()
+
+	app.Post("/", func(ctx aero.Context) error {
+		_, err := ctx.Request().Body().Multipart(aero.MultipartOptions{})
+		assert.True(t, errors.Is(err, aero.ErrNotMultipart))
+		return nil
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+	request.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(httptest.NewRecorder(), request)
+}
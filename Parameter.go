@@ -0,0 +1,7 @@
+package aero
+
+// Parameter is a named value extracted from the request path or host.
+type Parameter struct {
+	Name  string
+	Value string
+}
@@ -0,0 +1,210 @@
+package aero
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errInvalidRange is returned for Range headers with an invalid syntax.
+// Such headers are ignored and the full response is sent.
+var errInvalidRange = errors.New("Invalid range")
+
+// byteRange is a range of the response body.
+type byteRange struct {
+	start  int
+	length int
+}
+
+// contentRange returns the value of the Content-Range header for the range.
+func (r byteRange) contentRange(size int) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// writeRanges responds with the requested ranges of the body.
+// It returns false if the Range header should be ignored
+// and the full body should be sent instead.
+func (ctx *context) writeRanges(body []byte, contentType string) (bool, error) {
+	ranges, err := parseRange(ctx.request.inner.Header.Get(rangeHeader), len(body))
+
+	if err != nil {
+		return false, nil
+	}
+
+	response := ctx.response.inner
+	header := response.Header()
+
+	if len(ranges) == 0 {
+		header.Set(contentRangeHeader, "bytes */"+strconv.Itoa(len(body)))
+		header.Del(contentTypeHeader)
+		response.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return true, nil
+	}
+
+	total := 0
+
+	for _, r := range ranges {
+		total += r.length
+	}
+
+	// Overlapping ranges that add up to more than the body are
+	// more expensive than the full response, so ignore them.
+	if total > len(body) {
+		return false, nil
+	}
+
+	if len(ranges) == 1 {
+		r := ranges[0]
+		header.Set(contentRangeHeader, r.contentRange(len(body)))
+		header.Set(contentLengthHeader, strconv.Itoa(r.length))
+		response.WriteHeader(http.StatusPartialContent)
+		_, err = response.Write(body[r.start : r.start+r.length])
+		return true, err
+	}
+
+	buffer := bytes.Buffer{}
+	writer := multipart.NewWriter(&buffer)
+
+	for _, r := range ranges {
+		partHeader := textproto.MIMEHeader{}
+		partHeader.Set(contentRangeHeader, r.contentRange(len(body)))
+
+		if contentType != "" {
+			partHeader.Set(contentTypeHeader, contentType)
+		}
+
+		part, err := writer.CreatePart(partHeader)
+
+		if err != nil {
+			return true, err
+		}
+
+		_, err = part.Write(body[r.start : r.start+r.length])
+
+		if err != nil {
+			return true, err
+		}
+	}
+
+	err = writer.Close()
+
+	if err != nil {
+		return true, err
+	}
+
+	header.Set(contentTypeHeader, "multipart/byteranges; boundary="+writer.Boundary())
+	header.Set(contentLengthHeader, strconv.Itoa(buffer.Len()))
+	response.WriteHeader(http.StatusPartialContent)
+	_, err = response.Write(buffer.Bytes())
+	return true, err
+}
+
+// ifRange reports whether the If-Range header, if present, matches
+// the current entity tag or modification time of the response.
+// Otherwise the Range header must be ignored.
+func (ctx *context) ifRange() bool {
+	ifRange := strings.TrimSpace(ctx.request.inner.Header.Get(ifRangeHeader))
+
+	if ifRange == "" {
+		return true
+	}
+
+	header := ctx.response.inner.Header()
+
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return matchETag(ifRange, header.Get(etagHeader), false)
+	}
+
+	since, err := http.ParseTime(ifRange)
+
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(header.Get(lastModifiedHeader))
+	return err == nil && lastModified.Truncate(time.Second).Equal(since)
+}
+
+// parseRange parses the Range header for a body of the given size.
+// Ranges that start beyond the end of the body are left out,
+// so an empty result means that the range is not satisfiable.
+func parseRange(header string, size int) ([]byteRange, error) {
+	const prefix = "bytes="
+
+	if !strings.HasPrefix(header, prefix) {
+		return nil, errInvalidRange
+	}
+
+	var ranges []byteRange
+
+	for _, spec := range strings.Split(header[len(prefix):], ",") {
+		spec = strings.TrimSpace(spec)
+
+		if spec == "" {
+			continue
+		}
+
+		dash := strings.IndexByte(spec, '-')
+
+		if dash == -1 {
+			return nil, errInvalidRange
+		}
+
+		first := strings.TrimSpace(spec[:dash])
+		last := strings.TrimSpace(spec[dash+1:])
+
+		// Suffix range, e.g. -500 for the last 500 bytes
+		if first == "" {
+			length, err := strconv.Atoi(last)
+
+			if err != nil || length < 0 {
+				return nil, errInvalidRange
+			}
+
+			if length == 0 || size == 0 {
+				continue
+			}
+
+			if length > size {
+				length = size
+			}
+
+			ranges = append(ranges, byteRange{start: size - length, length: length})
+			continue
+		}
+
+		start, err := strconv.Atoi(first)
+
+		if err != nil || start < 0 {
+			return nil, errInvalidRange
+		}
+
+		end := size - 1
+
+		if last != "" {
+			end, err = strconv.Atoi(last)
+
+			if err != nil || end < start {
+				return nil, errInvalidRange
+			}
+
+			if end >= size {
+				end = size - 1
+			}
+		}
+
+		if start >= size {
+			continue
+		}
+
+		ranges = append(ranges, byteRange{start: start, length: end - start + 1})
+	}
+
+	return ranges, nil
+}
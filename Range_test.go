@@ -0,0 +1,168 @@
+package aero_test
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+func TestRange(t *testing.T) {
+	app := aero.New()
+	text := strings.Repeat("0123456789", 100)
+	size := strconv.Itoa(len(text))
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(text)
+	})
+
+	app.Get("/small", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	tests := []struct {
+		path         string
+		header       string
+		code         int
+		contentRange string
+		body         string
+	}{
+		{"/", "", http.StatusOK, "", text},
+		{"/", "bytes=0-9", http.StatusPartialContent, "bytes 0-9/" + size, text[:10]},
+		{"/", "bytes=990-", http.StatusPartialContent, "bytes 990-999/" + size, text[990:]},
+		{"/", "bytes=-5", http.StatusPartialContent, "bytes 995-999/" + size, text[995:]},
+		{"/", "bytes=995-2000", http.StatusPartialContent, "bytes 995-999/" + size, text[995:]},
+		{"/", "bytes=1000-", http.StatusRequestedRangeNotSatisfiable, "bytes */" + size, ""},
+		{"/", "bytes=9-5", http.StatusOK, "", text},
+		{"/", "lines=1-2", http.StatusOK, "", text},
+		{"/", "bytes=0-999,0-999", http.StatusOK, "", text},
+		{"/small", "bytes=0-4", http.StatusPartialContent, "bytes 0-4/" + strconv.Itoa(len(helloWorld)), helloWorld[:5]},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, test.path, nil)
+		request.Header.Set("Accept-Encoding", "gzip")
+
+		if test.header != "" {
+			request.Header.Set("Range", test.header)
+		}
+
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, test.code)
+		assert.Equal(t, response.Header().Get("Accept-Ranges"), "bytes")
+		assert.Equal(t, response.Header().Get("Content-Range"), test.contentRange)
+
+		if test.header == "" {
+			assert.Equal(t, response.Header().Get("Content-Encoding"), "gzip")
+			continue
+		}
+
+		assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+		assert.Equal(t, response.Body.String(), test.body)
+	}
+}
+
+func TestRangeMultiple(t *testing.T) {
+	app := aero.New()
+	text := strings.Repeat("0123456789", 100)
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(text)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Range", "bytes=0-4, 10-14, -3")
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusPartialContent)
+	assert.Equal(t, response.Header().Get("Content-Length"), strconv.Itoa(response.Body.Len()))
+
+	mediaType, params, err := mime.ParseMediaType(response.Header().Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, mediaType, "multipart/byteranges")
+
+	reader := multipart.NewReader(response.Body, params["boundary"])
+	expected := []struct {
+		contentRange string
+		body         string
+	}{
+		{"bytes 0-4/1000", "01234"},
+		{"bytes 10-14/1000", "01234"},
+		{"bytes 997-999/1000", "789"},
+	}
+
+	for _, part := range expected {
+		next, err := reader.NextPart()
+		assert.Nil(t, err)
+		assert.Equal(t, next.Header.Get("Content-Type"), "text/plain; charset=utf-8")
+		assert.Equal(t, next.Header.Get("Content-Range"), part.contentRange)
+		body, err := io.ReadAll(next)
+		assert.Nil(t, err)
+		assert.Equal(t, string(body), part.body)
+	}
+
+	_, err = reader.NextPart()
+	assert.Equal(t, err, io.EOF)
+}
+
+func TestRangeIfRange(t *testing.T) {
+	app := aero.New()
+	text := strings.Repeat("0123456789", 100)
+	modified := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	etag := strconv.Quote(aero.ETagString(text))
+
+	app.Get("/", func(ctx aero.Context) error {
+		ctx.SetLastModified(modified)
+		return ctx.Text(text)
+	})
+
+	app.Get("/weak", func(ctx aero.Context) error {
+		ctx.SetETag(`W/"v1"`)
+		return ctx.Text(text)
+	})
+
+	tests := []struct {
+		path    string
+		ifRange string
+		code    int
+	}{
+		{"/", etag, http.StatusPartialContent},
+		{"/", `"outdated"`, http.StatusOK},
+		{"/", modified.Format(http.TimeFormat), http.StatusPartialContent},
+		{"/", modified.Add(-time.Hour).Format(http.TimeFormat), http.StatusOK},
+		{"/", "invalid", http.StatusOK},
+		{"/weak", `W/"v1"`, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, test.path, nil)
+		request.Header.Set("Range", "bytes=0-9")
+		request.Header.Set("If-Range", test.ifRange)
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, test.code)
+
+		if test.code == http.StatusOK {
+			assert.Equal(t, response.Body.String(), text)
+		} else {
+			assert.Equal(t, response.Body.String(), text[:10])
+		}
+	}
+
+	// Preconditions are evaluated before the range
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Range", "bytes=0-9")
+	request.Header.Set("If-None-Match", etag)
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusNotModified)
+}
@@ -16,6 +16,7 @@ type Request interface {
 	Path() string
 	Protocol() string
 	Scheme() string
+	SetInternal(*http.Request)
 }
 
 // request represents the HTTP request used in the given context.
@@ -26,7 +27,8 @@ type request struct {
 // Body represents the request body.
 func (req *request) Body() RequestBody {
 	return RequestBody{
-		reader: req.inner.Body,
+		reader:      req.inner.Body,
+		contentType: req.inner.Header.Get(contentTypeHeader),
 	}
 }
 
@@ -82,3 +84,11 @@ func (req *request) Scheme() string {
 func (req *request) Internal() *http.Request {
 	return req.inner
 }
+
+// SetInternal sets the underlying *http.Request.
+// This method should be avoided unless absolutely necessary
+// because Aero doesn't guarantee that the underlying framework
+// will always stay net/http based in the future.
+func (req *request) SetInternal(request *http.Request) {
+	req.inner = request
+}
@@ -4,13 +4,17 @@ import (
 	"encoding/json"
 	"io"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
 
 	"github.com/akyoto/stringutils/unsafe"
 )
 
 // RequestBody represents a request body.
 type RequestBody struct {
-	reader io.ReadCloser
+	reader      io.ReadCloser
+	contentType string
 }
 
 // Reader returns an io.Reader for the request body.
@@ -18,6 +22,35 @@ func (body RequestBody) Reader() io.ReadCloser {
 	return body.reader
 }
 
+// Multipart returns a reader that streams the parts of a multipart body,
+// e.g. a multipart/form-data upload, one after another.
+func (body RequestBody) Multipart(options MultipartOptions) (*MultipartReader, error) {
+	mediaType, params, err := mime.ParseMediaType(body.contentType)
+
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, ErrNotMultipart
+	}
+
+	if body.reader == nil {
+		return nil, ErrEmptyBody
+	}
+
+	var reader io.Reader = body.reader
+
+	if options.MaxTotalSize > 0 {
+		reader = &limitedReader{
+			reader:    reader,
+			remaining: options.MaxTotalSize,
+			err:       ErrBodyTooLarge,
+		}
+	}
+
+	return &MultipartReader{
+		reader:  multipart.NewReader(reader, params["boundary"]),
+		options: options,
+	}, nil
+}
+
 // JSON parses the body as a JSON object.
 func (body RequestBody) JSON() (interface{}, error) {
 	if body.reader == nil {
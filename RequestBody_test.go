@@ -2,7 +2,11 @@ package aero_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -90,3 +94,122 @@ func TestRequestBodyErrors(t *testing.T) {
 	app.ServeHTTP(response, request)
 	assert.Equal(t, response.Code, http.StatusOK)
 }
+
+func TestRequestBodyLimit(t *testing.T) {
+	app := aero.New()
+	app.Config.Limits.Body = 10
+
+	handler := func(ctx aero.Context) error {
+		body, err := ctx.Request().Body().String()
+
+		if errors.Is(err, aero.ErrBodyTooLarge) {
+			return ctx.Error(http.StatusRequestEntityTooLarge, err)
+		}
+
+		if err != nil {
+			return ctx.Error(http.StatusBadRequest, err)
+		}
+
+		return ctx.Text(body)
+	}
+
+	app.Post("/", handler)
+	app.Post("/upload", handler).BodyLimit(100)
+
+	tests := []struct {
+		path    string
+		body    string
+		chunked bool
+		code    int
+	}{
+		{"/", "0123456789", false, http.StatusOK},
+		{"/", "0123456789+", false, http.StatusRequestEntityTooLarge},
+		{"/", "0123456789+", true, http.StatusRequestEntityTooLarge},
+		{"/upload", strings.Repeat("x", 100), false, http.StatusOK},
+		{"/upload", strings.Repeat("x", 101), true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+
+		if test.chunked {
+			request.ContentLength = -1
+		}
+
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, test.code)
+
+		if test.code == http.StatusOK {
+			assert.Equal(t, response.Body.String(), test.body)
+		}
+	}
+}
+
+func TestRequestBodyDecompression(t *testing.T) {
+	app := aero.New()
+	app.Config.Limits.DecodedBody = 1000
+
+	app.Post("/", func(ctx aero.Context) error {
+		body, err := ctx.Request().Body().String()
+
+		if errors.Is(err, aero.ErrBodyTooLarge) {
+			return ctx.Error(http.StatusRequestEntityTooLarge, err)
+		}
+
+		if errors.Is(err, aero.ErrUnsupportedContentEncoding) {
+			return ctx.Error(http.StatusUnsupportedMediaType, err)
+		}
+
+		if err != nil {
+			return ctx.Error(http.StatusBadRequest, err)
+		}
+
+		return ctx.Text(body)
+	})
+
+	app.Post("/ignore", func(ctx aero.Context) error {
+		return ctx.Text(helloWorld)
+	})
+
+	compress := func(encoding string, data string) *bytes.Buffer {
+		buffer := &bytes.Buffer{}
+		var writer io.WriteCloser = gzip.NewWriter(buffer)
+
+		if encoding == "deflate" {
+			writer = zlib.NewWriter(buffer)
+		}
+
+		_, err := writer.Write([]byte(data))
+		assert.Nil(t, err)
+		assert.Nil(t, writer.Close())
+		return buffer
+	}
+
+	tests := []struct {
+		path     string
+		encoding string
+		body     io.Reader
+		code     int
+		response string
+	}{
+		{"/", "gzip", compress("gzip", helloWorld), http.StatusOK, helloWorld},
+		{"/", "deflate", compress("deflate", helloWorld), http.StatusOK, helloWorld},
+		{"/", "gzip", compress("gzip", strings.Repeat("x", 1001)), http.StatusRequestEntityTooLarge, ""},
+		{"/", "gzip", strings.NewReader(helloWorld), http.StatusBadRequest, ""},
+		{"/", "br", strings.NewReader(helloWorld), http.StatusUnsupportedMediaType, ""},
+		{"/ignore", "br", strings.NewReader(helloWorld), http.StatusOK, helloWorld},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodPost, test.path, test.body)
+		request.Header.Set("Content-Encoding", test.encoding)
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, test.code)
+
+		if test.code == http.StatusOK {
+			assert.Equal(t, response.Body.String(), test.response)
+		}
+	}
+}
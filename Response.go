@@ -42,3 +42,23 @@ func (res *response) Internal() http.ResponseWriter {
 func (res *response) SetInternal(writer http.ResponseWriter) {
 	res.inner = writer
 }
+
+// headResponse discards the body of HEAD requests that
+// are served by a GET handler while keeping the headers.
+type headResponse struct {
+	http.ResponseWriter
+}
+
+// Write discards the data.
+func (res headResponse) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+// Flush implements the http.Flusher interface.
+func (res headResponse) Flush() {
+	flusher, ok := res.ResponseWriter.(http.Flusher)
+
+	if ok {
+		flusher.Flush()
+	}
+}
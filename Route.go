@@ -0,0 +1,259 @@
+package aero
+
+import (
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+// packagePath is the import path of this package.
+const packagePath = "github.com/aerogo/aero"
+
+// Route represents a handler registered for a method and path.
+type Route struct {
+	method     string
+	path       string
+	paths      []string
+	name       string
+	raw        Handler
+	handler    Handler
+	bound      Handler
+	middleware []Middleware
+	tags       []string
+	metadata   map[string]interface{}
+	bodyLimit  int64
+	hasLimit   bool
+	location   string
+	router     *Router
+}
+
+// RouteInfo describes a registered route.
+// Middleware is the number of route-specific middleware
+// and does not include the application-wide middleware.
+type RouteInfo struct {
+	Method     string
+	Path       string
+	Name       string
+	Middleware int
+	Tags       []string
+	Metadata   map[string]interface{}
+}
+
+// Method returns the HTTP method of the route.
+func (route *Route) Method() string {
+	return route.method
+}
+
+// Path returns the path pattern of the route, e.g. /user/:nick.
+func (route *Route) Path() string {
+	return route.path
+}
+
+// Name assigns a name to the route which can be used to generate URLs.
+func (route *Route) Name(name string) *Route {
+	route.router.setName(name, route)
+	return route
+}
+
+// Use adds middleware that only applies to this route.
+// It must be called before the application starts.
+func (route *Route) Use(middleware ...Middleware) *Route {
+	route.middleware = append(route.middleware[:len(route.middleware):len(route.middleware)], middleware...)
+	route.handler = route.raw.Bind(route.middleware...)
+	route.bound = route.handler
+	return route
+}
+
+// Tag adds tags to the route, e.g. to group routes in documentation.
+func (route *Route) Tag(tags ...string) *Route {
+	route.tags = append(route.tags, tags...)
+	return route
+}
+
+// Tags returns the tags of the route.
+func (route *Route) Tags() []string {
+	return route.tags
+}
+
+// Set attaches metadata to the route, e.g. a summary or the required role.
+// The metadata is available to middleware and handlers via ctx.Route().
+func (route *Route) Set(key string, value interface{}) *Route {
+	if route.metadata == nil {
+		route.metadata = map[string]interface{}{}
+	}
+
+	route.metadata[key] = value
+	return route
+}
+
+// Get returns the metadata for the given key or nil if it doesn't exist.
+func (route *Route) Get(key string) interface{} {
+	return route.metadata[key]
+}
+
+// BodyLimit overrides the configured maximum request body size in bytes
+// for this route, both before and after decompression.
+// A limit of 0 means that there is no limit.
+func (route *Route) BodyLimit(size int64) *Route {
+	route.bodyLimit = size
+	route.hasLimit = true
+	return route
+}
+
+// URL returns the path of the route with the parameters filled in.
+// Parameters are given as name and value pairs, e.g. "nick", "alice".
+// Optional parameters without a value are left out.
+func (route *Route) URL(params ...string) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("%w: %s", ErrInvalidParameters, route.path)
+	}
+
+	path := route.path
+	skipped := ""
+	buffer := strings.Builder{}
+	buffer.Grow(len(path))
+
+	for {
+		static, next, rest := nextToken(path)
+
+		if next.kind == 0 {
+			buffer.WriteString(static)
+			break
+		}
+
+		path = rest
+		value, found := parameterValue(next.name, params)
+
+		if next.optional && value == "" {
+			if skipped == "" {
+				skipped = next.name
+			}
+
+			buffer.WriteString(strings.TrimSuffix(static, "/"))
+			continue
+		}
+
+		if skipped != "" {
+			return "", fmt.Errorf("%w: '%s' in %s", ErrMissingParameter, skipped, route.path)
+		}
+
+		if !found || (next.kind == parameter && value == "") {
+			return "", fmt.Errorf("%w: '%s' in %s", ErrMissingParameter, next.name, route.path)
+		}
+
+		buffer.WriteString(static)
+
+		switch next.kind {
+		case parameter:
+			buffer.WriteString(url.PathEscape(value))
+
+		case wildcard:
+			segments := strings.Split(value, "/")
+
+			for index, segment := range segments {
+				if index != 0 {
+					buffer.WriteByte(separator)
+				}
+
+				buffer.WriteString(url.PathEscape(segment))
+			}
+		}
+	}
+
+	if buffer.Len() == 0 {
+		return "/", nil
+	}
+
+	return buffer.String(), nil
+}
+
+// parameterValue finds the value for the given name in a list of name and value pairs.
+func parameterValue(name string, params []string) (string, bool) {
+	for i := 0; i < len(params); i += 2 {
+		if params[i] == name {
+			return params[i+1], true
+		}
+	}
+
+	return "", false
+}
+
+// parameterConflict checks whether two paths reach the same parameter
+// position under different parameter names and returns both segments.
+func parameterConflict(a string, b string) (string, string, bool) {
+	segmentsA := splitSegments(a)
+	segmentsB := splitSegments(b)
+
+	for i := 0; i < len(segmentsA) && i < len(segmentsB); i++ {
+		segmentA := segmentsA[i]
+		segmentB := segmentsB[i]
+
+		if segmentA == segmentB {
+			continue
+		}
+
+		if segmentShape(segmentA) != segmentShape(segmentB) {
+			return "", "", false
+		}
+
+		return segmentA, segmentB, true
+	}
+
+	return "", "", false
+}
+
+// constraintConflict checks whether two paths reach the same parameter
+// position with overlapping constraints and returns both segments.
+func constraintConflict(a string, b string) (string, string, bool) {
+	segmentsA := splitSegments(a)
+	segmentsB := splitSegments(b)
+
+	for i := 0; i < len(segmentsA) && i < len(segmentsB); i++ {
+		segmentA := segmentsA[i]
+		segmentB := segmentsB[i]
+
+		if segmentA == segmentB {
+			continue
+		}
+
+		staticA, tokenA, restA := nextToken(segmentA)
+		staticB, tokenB, restB := nextToken(segmentB)
+
+		if staticA != "" || staticB != "" || restA != "" || restB != "" || tokenA.kind != parameter || tokenB.kind != parameter {
+			return "", "", false
+		}
+
+		if tokenA.constraint == "" || tokenB.constraint == "" {
+			return "", "", false
+		}
+
+		if !newConstraint(tokenA.constraint).overlaps(newConstraint(tokenB.constraint)) {
+			return "", "", false
+		}
+
+		return segmentA, segmentB, true
+	}
+
+	return "", "", false
+}
+
+// callerLocation returns the file and line of the
+// first caller outside of this package.
+func callerLocation() string {
+	var pcs [16]uintptr
+	count := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:count])
+
+	for {
+		frame, more := frames.Next()
+
+		if !strings.HasPrefix(frame.Function, packagePath+".") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+
+		if !more {
+			return "unknown location"
+		}
+	}
+}
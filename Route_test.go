@@ -0,0 +1,196 @@
+package aero_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+func TestRouteURL(t *testing.T) {
+	app := aero.New()
+	handler := func(ctx aero.Context) error { return nil }
+
+	app.Get("/", handler).Name("home")
+	app.Get("/user/:nick", handler).Name("user")
+	app.Get("/user/:nick/posts/:id", handler).Name("post")
+	app.Group("/files").Get("/*file", handler).Name("file")
+	app.Get("/archive/:year/:month?", handler).Name("archive")
+	app.Get("/img/:name.:ext", handler).Name("image")
+	app.Get("/download/*path.zip", handler).Name("download")
+	app.Get("/pages/:page?", handler).Name("page")
+
+	url, err := app.URL("home")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/")
+
+	url, err = app.URL("user", "nick", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/user/alice")
+
+	url, err = app.URL("user", "nick", "a/b c")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/user/a%2Fb%20c")
+
+	url, err = app.URL("post", "id", "42", "nick", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/user/alice/posts/42")
+
+	url, err = app.URL("file", "file", "images/my photo.png")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/files/images/my%20photo.png")
+
+	url, err = app.URL("archive", "year", "2020")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/archive/2020")
+
+	url, err = app.URL("archive", "year", "2020", "month", "05")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/archive/2020/05")
+
+	url, err = app.URL("image", "name", "logo", "ext", "png")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/img/logo.png")
+
+	url, err = app.URL("download", "path", "releases/v1")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/download/releases/v1.zip")
+
+	url, err = app.URL("page")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/pages")
+
+	_, err = app.URL("post", "nick", "alice")
+	assert.True(t, errors.Is(err, aero.ErrMissingParameter))
+
+	_, err = app.URL("user", "nick")
+	assert.True(t, errors.Is(err, aero.ErrInvalidParameters))
+
+	_, err = app.URL("unknown")
+	assert.True(t, errors.Is(err, aero.ErrUnknownRoute))
+}
+
+func TestRouteURLFor(t *testing.T) {
+	app := aero.New()
+
+	app.Get("/user/:nick", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("nick"))
+	}).Name("user")
+
+	app.Get("/", func(ctx aero.Context) error {
+		url, err := ctx.URLFor("user", "nick", "alice")
+
+		if err != nil {
+			return err
+		}
+
+		return ctx.Redirect(http.StatusFound, url)
+	})
+
+	response := test(app, "/")
+	assert.Equal(t, response.Code, http.StatusFound)
+	assert.Equal(t, response.Header().Get("Location"), "/user/alice")
+}
+
+func TestRouteNameConflict(t *testing.T) {
+	app := aero.New()
+	handler := func(ctx aero.Context) error { return nil }
+	app.Get("/a", handler).Name("page")
+
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, r.(error).Error(), "already used by GET /a")
+	}()
+
+	app.Get("/b", handler).Name("page")
+}
+
+func TestRouteMiddleware(t *testing.T) {
+	app := aero.New()
+
+	mark := func(name string) aero.Middleware {
+		return func(next aero.Handler) aero.Handler {
+			return func(ctx aero.Context) error {
+				ctx.Response().SetHeader("X-Order", ctx.Response().Header("X-Order")+name)
+				return next(ctx)
+			}
+		}
+	}
+
+	handler := func(ctx aero.Context) error {
+		return ctx.Text(ctx.Response().Header("X-Order"))
+	}
+
+	app.Use(mark("a"))
+	app.Get("/", handler, mark("r"))
+	app.Get("/fluent", handler).Use(mark("1"), mark("2"))
+	app.Group("/admin", mark("g")).Get("/", handler, mark("r")).Use(mark("u"))
+	app.Get("/plain", handler)
+	app.BindMiddleware()
+
+	tests := map[string]string{
+		"/":       "ar",
+		"/fluent": "a12",
+		"/admin":  "agru",
+		"/plain":  "a",
+	}
+
+	for path, body := range tests {
+		response := test(app, path)
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), body)
+	}
+
+	routes := app.Router().Routes()
+	assert.Equal(t, routes[0].Path, "/")
+	assert.Equal(t, routes[0].Middleware, 1)
+	assert.Equal(t, routes[1].Path, "/admin")
+	assert.Equal(t, routes[1].Middleware, 3)
+}
+
+func TestRouteMetadata(t *testing.T) {
+	app := aero.New()
+
+	requireRole := func(next aero.Handler) aero.Handler {
+		return func(ctx aero.Context) error {
+			role, _ := ctx.Route().Get("role").(string)
+
+			if role != "" && ctx.Request().Header("X-Role") != role {
+				return ctx.Error(http.StatusForbidden)
+			}
+
+			return next(ctx)
+		}
+	}
+
+	app.Use(requireRole)
+
+	app.Get("/users", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Route().Get("summary").(string))
+	}).Tag("admin", "users").Set("summary", "List users").Set("role", "admin")
+
+	app.BindMiddleware()
+
+	response := test(app, "/users")
+	assert.Equal(t, response.Code, http.StatusForbidden)
+
+	request := httptest.NewRequest(http.MethodGet, "/users", nil)
+	request.Header.Set("X-Role", "admin")
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "List users")
+
+	routes := app.Router().Routes()
+	assert.DeepEqual(t, routes[0].Tags, []string{"admin", "users"})
+	assert.Equal(t, routes[0].Metadata["summary"], "List users")
+
+	buffer := strings.Builder{}
+	app.PrintRoutes(&buffer)
+	assert.Contains(t, buffer.String(), "admin, users")
+}
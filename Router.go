@@ -4,8 +4,28 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 )
 
+// methods lists the HTTP methods known to the router
+// in the order they are reported in the Allow header.
+var methods = [...]string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// anyMethod is the method of routes that respond to every method
+// without a route of its own, e.g. the routes of mounted handlers.
+const anyMethod = "*"
+
 // Router is a high-performance router.
 type Router struct {
 	get     tree
@@ -17,17 +37,70 @@ type Router struct {
 	connect tree
 	trace   tree
 	options tree
+	custom  map[string]*tree
+	others  tree
+	routes  []*Route
+	named   map[string]*Route
 }
 
 // Add registers a new handler for the given method and path.
-func (router *Router) Add(method string, path string, handler Handler) {
-	tree := router.selectTree(method)
+// The middleware only applies to this route.
+func (router *Router) Add(method string, path string, handler Handler, middleware ...Middleware) *Route {
+	return router.add(method, path, handler, middleware)
+}
 
-	if tree == nil {
-		panic(fmt.Errorf("Unknown HTTP method: '%s'", method))
+// Remove unregisters the handler for the given method and path.
+// The path must be the same pattern that was used to register the route.
+// It returns false if no such route exists.
+func (router *Router) Remove(method string, path string) bool {
+	for index, route := range router.routes {
+		if route.method != method || route.path != path {
+			continue
+		}
+
+		if route.name != "" {
+			delete(router.named, route.name)
+		}
+
+		router.routes = append(router.routes[:index], router.routes[index+1:]...)
+		router.rebuild()
+		return true
 	}
 
-	tree.add(path, handler)
+	return false
+}
+
+// Clone returns a new router with the same routes.
+// The clone can be modified without affecting the original router
+// and is usually passed to SetRouter afterwards.
+func (router *Router) Clone() *Router {
+	clone := &Router{
+		routes: make([]*Route, 0, len(router.routes)),
+	}
+
+	for _, route := range router.routes {
+		copied := *route
+		copied.router = clone
+		copied.bound = copied.handler
+		copied.tags = append([]string(nil), route.tags...)
+		copied.metadata = nil
+
+		for key, value := range route.metadata {
+			copied.Set(key, value)
+		}
+		clone.routes = append(clone.routes, &copied)
+
+		if copied.name != "" {
+			if clone.named == nil {
+				clone.named = map[string]*Route{}
+			}
+
+			clone.named[copied.name] = &copied
+		}
+	}
+
+	clone.rebuild()
+	return clone
 }
 
 // Find returns the handler for the given route.
@@ -42,26 +115,125 @@ func (router *Router) Find(method string, path string) Handler {
 // Lookup finds the handler and parameters for the given route
 // and assigns them to the given context.
 func (router *Router) Lookup(method string, path string, ctx *context) {
-	if method[0] == 'G' {
+	ctx.route = nil
+
+	if method == http.MethodGet {
 		router.get.find(path, ctx)
+	} else {
+		tree := router.selectTree(method)
+
+		if tree != nil {
+			tree.find(path, ctx)
+		}
+
+		// Non-standard methods fall back to the routes for any method
+		if ctx.route == nil && methodOrder(method) == len(methods) {
+			router.others.find(path, ctx)
+		}
+	}
+
+	if ctx.route == nil {
+		ctx.handler = nil
 		return
 	}
 
-	tree := router.selectTree(method)
-	tree.find(path, ctx)
+	ctx.handler = ctx.route.bound
+}
+
+// Allowed returns the methods that have a handler registered for the given path.
+func (router *Router) Allowed(path string) []string {
+	return router.allowed(path, false)
 }
 
-// bind traverses all trees and calls the given function on every node.
+// allowed returns the methods that have a handler registered for the given path.
+// If exact is set, routes whose trailing slash differs from the path are ignored.
+func (router *Router) allowed(path string, exact bool) []string {
+	var allowed []string
+
+	for _, method := range methods {
+		if router.matches(method, path, exact) {
+			allowed = append(allowed, method)
+		}
+	}
+
+	custom := make([]string, 0, len(router.custom))
+
+	for method := range router.custom {
+		if router.matches(method, path, exact) {
+			custom = append(custom, method)
+		}
+	}
+
+	sort.Strings(custom)
+	return append(allowed, custom...)
+}
+
+// matches reports whether a route is registered for the method and path.
+// If exact is set, the trailing slash of the route must match the path.
+func (router *Router) matches(method string, path string, exact bool) bool {
+	ctx := context{}
+	router.Lookup(method, path, &ctx)
+
+	if ctx.route == nil {
+		return false
+	}
+
+	return !exact || canonicalTrailingSlash(path, ctx.route.path) == path
+}
+
+// Routes returns a description of every registered route,
+// sorted by path and method.
+func (router *Router) Routes() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(router.routes))
+
+	for _, route := range router.routes {
+		routes = append(routes, RouteInfo{
+			Method:     route.method,
+			Path:       route.path,
+			Name:       route.name,
+			Middleware: len(route.middleware),
+			Tags:       route.tags,
+			Metadata:   route.metadata,
+		})
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		a := methodOrder(routes[i].Method)
+		b := methodOrder(routes[j].Method)
+
+		if a != b {
+			return a < b
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// URL returns the path of the route with the given name and parameters.
+// Parameters are given as name and value pairs, e.g. "nick", "alice".
+func (router *Router) URL(name string, params ...string) (string, error) {
+	route, found := router.named[name]
+
+	if !found {
+		return "", fmt.Errorf("%w: '%s'", ErrUnknownRoute, name)
+	}
+
+	return route.URL(params...)
+}
+
+// bind calls the given function on the handler of every route
+// and uses the result when the route is requested.
+// Calling bind again replaces the previous result.
 func (router *Router) bind(transform func(Handler) Handler) {
-	router.get.bind(transform)
-	router.post.bind(transform)
-	router.delete.bind(transform)
-	router.put.bind(transform)
-	router.patch.bind(transform)
-	router.head.bind(transform)
-	router.connect.bind(transform)
-	router.trace.bind(transform)
-	router.options.bind(transform)
+	for _, route := range router.routes {
+		route.bound = transform(route.handler)
+	}
 }
 
 // Print shows a pretty print of the dynamic routes.
@@ -91,7 +263,162 @@ func (router *Router) selectTree(method string) *tree {
 		return &router.trace
 	case http.MethodOptions:
 		return &router.options
+	case anyMethod:
+		return &router.others
 	default:
-		return nil
+		return router.custom[method]
 	}
 }
+
+// add registers a new handler with the given route-specific middleware.
+// It panics when the route conflicts with an existing one.
+func (router *Router) add(method string, path string, handler Handler, middleware []Middleware) *Route {
+	route := &Route{
+		method:     method,
+		path:       path,
+		paths:      expandOptional(path),
+		middleware: middleware,
+		location:   callerLocation(),
+		router:     router,
+	}
+
+	router.checkConflicts(route)
+	tree := router.selectTree(method)
+
+	if tree == nil {
+		tree = router.addCustomTree(method)
+	}
+
+	route.raw = handler
+	route.handler = handler.Bind(middleware...)
+	route.bound = route.handler
+
+	for _, expanded := range route.paths {
+		tree.add(expanded, route)
+	}
+
+	router.routes = append(router.routes, route)
+	return route
+}
+
+// rebuild recreates the trees from the registered routes.
+func (router *Router) rebuild() {
+	routes := router.routes
+	named := router.named
+	*router = Router{
+		routes: routes,
+		named:  named,
+	}
+
+	for _, route := range routes {
+		tree := router.selectTree(route.method)
+
+		if tree == nil {
+			tree = router.addCustomTree(route.method)
+		}
+
+		for _, expanded := range route.paths {
+			tree.add(expanded, route)
+		}
+	}
+}
+
+// checkConflicts panics if the route has already been registered,
+// if it would share a parameter with a differently named one or
+// if its constraints overlap with the ones at the same position.
+func (router *Router) checkConflicts(route *Route) {
+	for _, existing := range router.routes {
+		if existing.method != route.method {
+			continue
+		}
+
+		for _, existingPath := range existing.paths {
+			for _, path := range route.paths {
+				if existingPath == path {
+					panic(fmt.Errorf("Duplicate route %s %s registered at %s and %s", route.method, path, existing.location, route.location))
+				}
+
+				a, b, conflict := parameterConflict(existingPath, path)
+
+				if conflict {
+					panic(fmt.Errorf("Route %s %s at %s conflicts with %s %s at %s: parameters '%s' and '%s' share the same position", route.method, route.path, route.location, existing.method, existing.path, existing.location, b, a))
+				}
+
+				a, b, conflict = constraintConflict(existingPath, path)
+
+				if conflict {
+					panic(fmt.Errorf("Route %s %s at %s conflicts with %s %s at %s: constraints of '%s' and '%s' overlap", route.method, route.path, route.location, existing.method, existing.path, existing.location, b, a))
+				}
+			}
+		}
+	}
+}
+
+// setName registers the route under the given name.
+func (router *Router) setName(name string, route *Route) {
+	existing, found := router.named[name]
+
+	if found && existing != route {
+		panic(fmt.Errorf("Route name '%s' is already used by %s %s", name, existing.method, existing.path))
+	}
+
+	if router.named == nil {
+		router.named = map[string]*Route{}
+	}
+
+	if route.name != "" {
+		delete(router.named, route.name)
+	}
+
+	route.name = name
+	router.named[name] = route
+}
+
+// addCustomTree creates the tree for a non-standard HTTP method.
+func (router *Router) addCustomTree(method string) *tree {
+	if !isToken(method) {
+		panic(fmt.Errorf("Invalid HTTP method: '%s'", method))
+	}
+
+	if router.custom == nil {
+		router.custom = map[string]*tree{}
+	}
+
+	custom := &tree{}
+	router.custom[method] = custom
+	return custom
+}
+
+// methodOrder returns the sort order of the given method.
+// Non-standard methods are sorted after the standard ones.
+func methodOrder(method string) int {
+	for index, known := range methods {
+		if known == method {
+			return index
+		}
+	}
+
+	return len(methods)
+}
+
+// isToken reports whether the method is a valid token as defined in RFC 7230.
+func isToken(method string) bool {
+	if method == "" {
+		return false
+	}
+
+	for i := 0; i < len(method); i++ {
+		char := method[i]
+
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+			continue
+		case strings.IndexByte("!#$%&'*+-.^_`|~", char) != -1:
+			continue
+		default:
+			return false
+		}
+	}
+
+	return true
+}
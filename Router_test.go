@@ -2,6 +2,9 @@ package aero_test
 
 import (
 	"bufio"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -202,3 +205,382 @@ func loadRoutes(filePath string) []routeDefinition {
 	f.Close()
 	return routes
 }
+
+func TestRouterAllowed(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+
+	router.Add("GET", "/user/:id", page)
+	router.Add("DELETE", "/user/:id", page)
+	router.Add("POST", "/user", page)
+
+	assert.DeepEqual(t, router.Allowed("/user/42"), []string{"GET", "DELETE"})
+	assert.DeepEqual(t, router.Allowed("/user"), []string{"POST"})
+	assert.Equal(t, len(router.Allowed("/404")), 0)
+}
+
+func TestRouterCustomMethods(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+
+	assert.Nil(t, router.Find("PROPFIND", "/"))
+	router.Add("PROPFIND", "/files/*file", page)
+	assert.NotNil(t, router.Find("PROPFIND", "/files/readme.txt"))
+	assert.Nil(t, router.Find("PROPPATCH", "/files/readme.txt"))
+
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, r.(error).Error(), "Invalid HTTP method")
+	}()
+
+	router.Add("NOT VALID", "/", page)
+}
+
+func TestRouterConstraints(t *testing.T) {
+	router := aero.Router{}
+	id := func(aero.Context) error { return nil }
+	slug := func(aero.Context) error { return nil }
+	uuid := func(aero.Context) error { return nil }
+
+	router.Add("GET", "/post/:id<int>", id)
+	router.Add("GET", "/post/:uuid<uuid>", uuid)
+	router.Add("GET", "/post/:slug<[a-z][a-z-]*>", slug)
+	router.Add("GET", "/post/:id<int>/comments", id)
+	router.Add("GET", "/number/:n<int>", id)
+
+	assert.NotNil(t, router.Find("GET", "/post/123"))
+	assert.NotNil(t, router.Find("GET", "/post/-123"))
+	assert.NotNil(t, router.Find("GET", "/post/123/"))
+	assert.NotNil(t, router.Find("GET", "/post/123/comments"))
+	assert.NotNil(t, router.Find("GET", "/post/hello-world"))
+	assert.NotNil(t, router.Find("GET", "/post/6ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	assert.Nil(t, router.Find("GET", "/post/Hello"))
+	assert.Nil(t, router.Find("GET", "/post/hello/comments"))
+	assert.Nil(t, router.Find("GET", "/number/abc"))
+	assert.Nil(t, router.Find("GET", "/number/"))
+
+	// Constrained parameters take precedence over unconstrained ones
+	any := func(aero.Context) error { return nil }
+	router.Add("GET", "/number/:name", any)
+	assert.NotNil(t, router.Find("GET", "/number/abc"))
+	assert.NotNil(t, router.Find("GET", "/number/123"))
+}
+
+func TestRouterConstraintsAmbiguous(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+	router.Add("GET", "/post/:id<int>", page)
+
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, r.(error).Error(), "share the same position")
+	}()
+
+	router.Add("GET", "/post/:number<int>/comments", page)
+}
+
+func TestRouterConstraintsBacktracking(t *testing.T) {
+	router := aero.Router{}
+	edit := func(aero.Context) error { return nil }
+	slug := func(aero.Context) error { return nil }
+	view := func(aero.Context) error { return nil }
+
+	router.Add("GET", "/post/:id<int>/edit", edit)
+	router.Add("GET", "/post/:slug", slug)
+	router.Add("GET", "/article/:id<int>/edit", edit)
+	router.Add("GET", "/article/:slug/view", view)
+
+	assert.NotNil(t, router.Find("GET", "/post/123"))
+	assert.NotNil(t, router.Find("GET", "/post/123/edit"))
+	assert.NotNil(t, router.Find("GET", "/post/hello"))
+	assert.NotNil(t, router.Find("GET", "/article/123/view"))
+	assert.NotNil(t, router.Find("GET", "/article/123/edit"))
+	assert.Nil(t, router.Find("GET", "/article/123/delete"))
+
+	app := aero.New()
+
+	app.Get("/post/:id<int>/edit", func(ctx aero.Context) error {
+		return ctx.Text("edit " + ctx.Get("id"))
+	})
+
+	app.Get("/post/:slug/view", func(ctx aero.Context) error {
+		return ctx.Text("view " + ctx.Get("slug") + " " + ctx.Get("id"))
+	})
+
+	request := httptest.NewRequest("GET", "/post/123/view", nil)
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), "view 123 ")
+}
+
+func TestRouterConstraintsOverlap(t *testing.T) {
+	tests := [][2]string{
+		{"/post/:id<int>", "/post/:n<[0-9]+>"},
+		{"/post/:id<uuid>/edit", "/post/:slug<[a-z0-9-]+>"},
+		{"/post/:slug<[a-z0-9-]+>", "/post/:id<uuid>"},
+	}
+
+	for _, test := range tests {
+		router := aero.Router{}
+		page := func(aero.Context) error { return nil }
+		router.Add("GET", test[0], page)
+
+		func() {
+			defer func() {
+				r := recover()
+				assert.NotNil(t, r)
+				assert.Contains(t, r.(error).Error(), "overlap")
+			}()
+
+			router.Add("GET", test[1], page)
+		}()
+	}
+
+	// Disjoint constraints are allowed
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+	router.Add("GET", "/post/:id<int>", page)
+	router.Add("GET", "/post/:uuid<uuid>", page)
+	router.Add("GET", "/post/:slug<[a-z][a-z-]*>", page)
+}
+
+func TestRouterConstraintsInvalid(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, r.(error).Error(), "Invalid parameter constraint")
+	}()
+
+	router.Add("GET", "/post/:id<[a-z>", page)
+}
+
+func TestRouterConstraintsSlash(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+
+	router.Add("GET", "/post/:slug<[^/]+>", page)
+	router.Add("GET", "/post/:slug<[^/]+>/comments", page)
+	router.Add("GET", "/tag/:name<[a-z/]+>", page)
+	router.Add("GET", "/archive/:year<[^/]+>?", page)
+	router.Add("GET", "/files/*path<[a-z/]+>", page)
+
+	assert.NotNil(t, router.Find("GET", "/post/hello"))
+	assert.NotNil(t, router.Find("GET", "/post/hello/comments"))
+	assert.NotNil(t, router.Find("GET", "/tag/go"))
+	assert.Nil(t, router.Find("GET", "/tag/go/web"))
+	assert.NotNil(t, router.Find("GET", "/archive"))
+	assert.NotNil(t, router.Find("GET", "/archive/2020"))
+	assert.NotNil(t, router.Find("GET", "/files/docs/readme"))
+	assert.Nil(t, router.Find("GET", "/files/docs/README"))
+}
+
+func TestRouterRoutes(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+
+	router.Add("POST", "/user", page)
+	router.Add("GET", "/user/:id", page).Name("user")
+	router.Add("GET", "/user", page)
+	router.Add("PROPFIND", "/files/*file", page)
+	router.Add("DELETE", "/user/:id", page)
+
+	assert.DeepEqual(t, router.Routes(), []aero.RouteInfo{
+		{Method: "PROPFIND", Path: "/files/*file"},
+		{Method: "GET", Path: "/user"},
+		{Method: "POST", Path: "/user"},
+		{Method: "GET", Path: "/user/:id", Name: "user"},
+		{Method: "DELETE", Path: "/user/:id"},
+	})
+}
+
+func TestRouterDuplicate(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+	router.Add("GET", "/user/:id", page)
+	router.Add("POST", "/user/:id", page)
+
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		message := r.(error).Error()
+		assert.Contains(t, message, "Duplicate route GET /user/:id")
+		assert.Contains(t, message, "Router_test.go")
+	}()
+
+	router.Add("GET", "/user/:id", page)
+}
+
+func TestRouterConflict(t *testing.T) {
+	conflicts := [][2]string{
+		{"/user/:id", "/user/:name"},
+		{"/user/:id/posts", "/user/:name/comments"},
+		{"/files/*file", "/files/*path"},
+		{"/img/:name.:ext", "/img/:file.:type"},
+		{"/files/*path.zip", "/files/*file.zip"},
+		{"/archive/:year/:month?", "/archive/:year/:day"},
+	}
+
+	for _, paths := range conflicts {
+		router := aero.Router{}
+		page := func(aero.Context) error { return nil }
+		router.Add("GET", paths[0], page)
+
+		func() {
+			defer func() {
+				r := recover()
+				assert.NotNil(t, r)
+				assert.Contains(t, r.(error).Error(), "conflicts with GET "+paths[0])
+			}()
+
+			router.Add("GET", paths[1], page)
+		}()
+	}
+
+	// Different constraints and diverging paths don't conflict
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+	router.Add("GET", "/user/:id<int>", page)
+	router.Add("GET", "/user/:name", page)
+	router.Add("GET", "/post/:id", page)
+	router.Add("GET", "/user/:name/posts/:id", page)
+	router.Add("GET", "/img/:name.:ext", page)
+	router.Add("GET", "/img/:name.png", page)
+	router.Add("GET", "/files/*path.zip", page)
+	router.Add("GET", "/files/*path.tar", page)
+}
+
+func TestRouterRemove(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+
+	router.Add("GET", "/user", page)
+	router.Add("GET", "/user/:id", page).Name("user")
+	router.Add("PROPFIND", "/files/*file", page)
+
+	assert.True(t, router.Remove("GET", "/user/:id"))
+	assert.False(t, router.Remove("GET", "/user/:id"))
+	assert.False(t, router.Remove("POST", "/user"))
+	assert.True(t, router.Remove("PROPFIND", "/files/*file"))
+
+	assert.Nil(t, router.Find("GET", "/user/1"))
+	assert.Nil(t, router.Find("PROPFIND", "/files/a.txt"))
+	assert.NotNil(t, router.Find("GET", "/user"))
+
+	_, err := router.URL("user", "id", "1")
+	assert.True(t, errors.Is(err, aero.ErrUnknownRoute))
+
+	// The path is free to be registered again
+	router.Add("GET", "/user/:nick", page)
+	assert.NotNil(t, router.Find("GET", "/user/alice"))
+}
+
+func TestRouterClone(t *testing.T) {
+	router := aero.Router{}
+	page := func(aero.Context) error { return nil }
+
+	router.Add("GET", "/", page)
+	router.Add("GET", "/user/:id", page).Name("user")
+
+	clone := router.Clone()
+	clone.Add("GET", "/landing/:page", page)
+	clone.Remove("GET", "/")
+
+	assert.NotNil(t, router.Find("GET", "/"))
+	assert.Nil(t, router.Find("GET", "/landing/summer"))
+	assert.Nil(t, clone.Find("GET", "/"))
+	assert.NotNil(t, clone.Find("GET", "/landing/summer"))
+
+	url, err := clone.URL("user", "id", "1")
+	assert.Nil(t, err)
+	assert.Equal(t, url, "/user/1")
+
+	clone.Add("GET", "/profile/:id", page).Name("profile")
+	_, err = router.URL("profile", "id", "1")
+	assert.NotNil(t, err)
+}
+
+func TestRouterOptional(t *testing.T) {
+	app := aero.New()
+
+	app.Get("/archive/:year<int>/:month?/:day?", func(ctx aero.Context) error {
+		return ctx.Text(ctx.Get("year") + "-" + ctx.Get("month") + "-" + ctx.Get("day"))
+	})
+
+	tests := map[string]string{
+		"/archive/2020":        "2020--",
+		"/archive/2020/":       "2020--",
+		"/archive/2020/05":     "2020-05-",
+		"/archive/2020/05/17":  "2020-05-17",
+		"/archive/2020/05/17/": "2020-05-17",
+	}
+
+	for path, body := range tests {
+		response := test(app, path)
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), body)
+	}
+
+	assert.Equal(t, test(app, "/archive").Code, http.StatusNotFound)
+	assert.Equal(t, test(app, "/archive/abc").Code, http.StatusNotFound)
+	assert.Equal(t, test(app, "/archive/2020/05/17/x").Code, http.StatusNotFound)
+
+	// Optional parameters must be at the end of the path
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r)
+		assert.Contains(t, r.(error).Error(), "must be at the end of the path")
+	}()
+
+	app.Get("/news/:year?/list", func(ctx aero.Context) error { return nil })
+}
+
+func TestRouterPatterns(t *testing.T) {
+	app := aero.New()
+
+	handler := func(ctx aero.Context) error {
+		params := []string{}
+
+		for _, param := range ctx.Params() {
+			params = append(params, param.Name+"="+param.Value)
+		}
+
+		return ctx.Text(strings.Join(params, ","))
+	}
+
+	app.Get("/img/:name.:ext", handler)
+	app.Get("/img/:name", handler)
+	app.Get("/posts/:id<int>.json", handler)
+	app.Get("/range/:from-:to/items", handler)
+	app.Get("/files/*path.zip", handler)
+	app.Get("/files/*path.tar.gz", handler)
+	app.Get("/files/*path", handler)
+	app.Get("/v:major.:minor/docs", handler)
+
+	tests := map[string]string{
+		"/img/logo.png":      "name=logo,ext=png",
+		"/img/logo.min.png":  "name=logo.min,ext=png",
+		"/img/logo":          "name=logo",
+		"/posts/42.json":     "id=42",
+		"/range/10-20/items": "from=10,to=20",
+		"/files/a/b.zip":     "path=a/b",
+		"/files/a/b.tar.gz":  "path=a/b",
+		"/files/a/b.txt":     "path=a/b.txt",
+		"/files/.zip":        "path=.zip",
+		"/v1.2/docs":         "major=1,minor=2",
+	}
+
+	for path, body := range tests {
+		response := test(app, path)
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), body)
+	}
+
+	assert.Equal(t, test(app, "/posts/abc.json").Code, http.StatusNotFound)
+	assert.Equal(t, test(app, "/range/10/items").Code, http.StatusNotFound)
+}
@@ -0,0 +1,415 @@
+package aero
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/xxh3"
+)
+
+// staticParameter is the name of the wildcard parameter
+// that contains the path of the requested static file.
+const staticParameter = "file"
+
+// fingerprint matches file names that contain a content hash,
+// e.g. app.3f2a9c1d.js or logo-8d3e5a0b12.png.
+var fingerprint = regexp.MustCompile(`[.-][0-9a-fA-F]{8,}\.[^./]+$`)
+
+// precompressed lists the content encodings of precompressed
+// siblings and their file extensions, e.g. app.js.br.
+var precompressed = []struct {
+	encoding  string
+	extension string
+}{
+	{contentEncodingBrotli, ".br"},
+	{contentEncodingZstd, ".zst"},
+	{contentEncodingGzip, ".gz"},
+}
+
+// StaticOptions configures how static files are served.
+type StaticOptions struct {
+	// Precompress compresses all files at startup with the registered
+	// compressors and keeps the results in memory. Encodings that
+	// already have a precompressed sibling file are skipped.
+	Precompress bool
+}
+
+// staticFiles serves the files of a file system.
+type staticFiles struct {
+	app        *Application
+	files      fs.FS
+	options    StaticOptions
+	cache      sync.Map
+	compressed sync.Map
+}
+
+// staticFile contains the metadata of a static file.
+// It is cached until the file is modified.
+type staticFile struct {
+	modTime     time.Time
+	size        int64
+	hash        string
+	contentType string
+	immutable   bool
+	variants    []staticVariant
+}
+
+// staticVariant is a compressed representation of a static file,
+// either a precompressed sibling or compressed data in memory.
+type staticVariant struct {
+	encoding string
+	name     string
+	data     []byte
+	size     int64
+}
+
+// Static serves the files in the directory below the prefix.
+func (group *Group) Static(prefix string, dir string, options ...StaticOptions) *Route {
+	return group.StaticFS(prefix, os.DirFS(dir), options...)
+}
+
+// StaticFS serves the files of the file system below the prefix,
+// e.g. an embed.FS. Precompressed siblings like app.js.br and app.js.gz
+// are served to clients that accept their encoding. Responses have strong
+// ETags and files with a content hash in their name are cached forever.
+func (group *Group) StaticFS(prefix string, files fs.FS, options ...StaticOptions) *Route {
+	static := &staticFiles{
+		app:   group.app,
+		files: files,
+	}
+
+	if len(options) > 0 {
+		static.options = options[0]
+	}
+
+	if static.options.Precompress {
+		err := static.precompress()
+
+		if err != nil {
+			panic(fmt.Errorf("Static files for '%s' could not be compressed: %w", prefix, err))
+		}
+	}
+
+	return group.add(http.MethodGet, strings.TrimSuffix(prefix, "/")+"/*"+staticParameter, static.serve, nil)
+}
+
+// precompress loads all files so that their compressed variants are cached.
+func (static *staticFiles) precompress() error {
+	return fs.WalkDir(static.files, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || isPrecompressed(name) {
+			return err
+		}
+
+		_, err = static.lookup(name)
+		return err
+	})
+}
+
+// serve responds with the requested file.
+func (static *staticFiles) serve(ctx Context) error {
+	name := path.Clean("/" + ctx.Get(staticParameter))[1:]
+
+	if name == "" || !fs.ValidPath(name) {
+		return ctx.Error(http.StatusNotFound)
+	}
+
+	file, err := static.lookup(name)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return ctx.Error(http.StatusNotFound)
+	}
+
+	if err != nil {
+		return ctx.Error(http.StatusInternalServerError, err)
+	}
+
+	request := ctx.Request().Internal()
+	response := ctx.Response().Internal()
+	header := response.Header()
+	header.Set(contentTypeHeader, file.contentType)
+
+	if file.immutable {
+		header.Set(cacheControlHeader, cacheControlImmutable)
+	} else {
+		header.Set(cacheControlHeader, cacheControlAlwaysValidate)
+	}
+
+	if len(file.variants) > 0 {
+		header.Add(varyHeader, acceptEncodingHeader)
+	}
+
+	variant := file.negotiate(request.Header.Get(acceptEncodingHeader))
+
+	if variant == nil {
+		header.Set(etagHeader, strconv.Quote(file.hash))
+		return static.serveFile(response, request, name, file.modTime)
+	}
+
+	header.Set(contentEncodingHeader, variant.encoding)
+	header.Set(etagHeader, strconv.Quote(file.hash+"-"+variant.encoding))
+
+	if variant.data != nil {
+		http.ServeContent(response, request, name, file.modTime, bytes.NewReader(variant.data))
+		return nil
+	}
+
+	return static.serveFile(response, request, variant.name, file.modTime)
+}
+
+// serveFile sends the contents of the file, including support for
+// range requests if the file system returns seekable files.
+func (static *staticFiles) serveFile(response http.ResponseWriter, request *http.Request, name string, modTime time.Time) error {
+	file, err := static.files.Open(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+	content, seekable := file.(io.ReadSeeker)
+
+	if !seekable {
+		data, err := io.ReadAll(file)
+
+		if err != nil {
+			return err
+		}
+
+		content = bytes.NewReader(data)
+	}
+
+	http.ServeContent(response, request, name, modTime, content)
+	return nil
+}
+
+// lookup returns the metadata of the file and reloads
+// it if the file has been modified since it was cached.
+func (static *staticFiles) lookup(name string) (*staticFile, error) {
+	info, err := fs.Stat(static.files, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return nil, fs.ErrNotExist
+	}
+
+	cached, found := static.cache.Load(name)
+
+	if found {
+		file := cached.(*staticFile)
+
+		if file.modTime.Equal(info.ModTime()) && file.size == info.Size() {
+			return file, nil
+		}
+	}
+
+	file, err := static.load(name, info)
+
+	if err != nil {
+		return nil, err
+	}
+
+	static.cache.Store(name, file)
+	return file, nil
+}
+
+// load calculates the hash of the file and finds its compressed variants.
+// The file is only read into memory if it needs to be precompressed.
+func (static *staticFiles) load(name string, info fs.FileInfo) (*staticFile, error) {
+	var (
+		data []byte
+		hash string
+		head []byte
+		err  error
+	)
+
+	if static.options.Precompress {
+		data, err = fs.ReadFile(static.files, name)
+		hash = ETag(data)
+		head = data
+	} else {
+		hash, head, err = hashFile(static.files, name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	file := &staticFile{
+		modTime:     info.ModTime(),
+		size:        info.Size(),
+		hash:        hash,
+		contentType: mime.TypeByExtension(path.Ext(name)),
+		immutable:   fingerprint.MatchString(path.Base(name)),
+	}
+
+	if file.contentType == "" {
+		file.contentType = http.DetectContentType(head)
+	}
+
+	for _, sibling := range precompressed {
+		siblingInfo, err := fs.Stat(static.files, name+sibling.extension)
+
+		if err != nil || siblingInfo.IsDir() {
+			continue
+		}
+
+		file.variants = append(file.variants, staticVariant{
+			encoding: sibling.encoding,
+			name:     name + sibling.extension,
+			size:     siblingInfo.Size(),
+		})
+	}
+
+	if !static.options.Precompress || len(data) < gzipThreshold || !canCompress(file.contentType) {
+		return file, nil
+	}
+
+	for _, compressor := range static.app.compressors {
+		if file.variant(compressor.encoding) != nil {
+			continue
+		}
+
+		compressed, err := static.compress(compressor, file.hash, data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(compressed) >= len(data) {
+			continue
+		}
+
+		file.variants = append(file.variants, staticVariant{
+			encoding: compressor.encoding,
+			data:     compressed,
+			size:     int64(len(compressed)),
+		})
+	}
+
+	return file, nil
+}
+
+// hashFile streams the file through the hash used by ETag.
+// It also returns the first bytes of the file for content type detection.
+func hashFile(files fs.FS, name string) (string, []byte, error) {
+	file, err := files.Open(name)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer file.Close()
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+
+	head = head[:n]
+	hasher := xxh3.New()
+	_, _ = hasher.Write(head)
+	_, err = io.Copy(hasher, file)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return strconv.FormatUint(hasher.Sum64(), 16), head, nil
+}
+
+// compress returns the compressed data. Files with the same
+// content share the compressed data via their content hash.
+func (static *staticFiles) compress(compressor *compressor, hash string, data []byte) ([]byte, error) {
+	key := hash + "-" + strconv.Itoa(len(data)) + "-" + compressor.encoding
+	cached, found := static.compressed.Load(key)
+
+	if found {
+		return cached.([]byte), nil
+	}
+
+	buffer := bytes.Buffer{}
+	writer, err := static.app.acquireCompressionWriter(compressor, &buffer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = writer.Write(data)
+	closeErr := writer.Close()
+	compressor.pool.Put(writer)
+
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	static.compressed.Store(key, buffer.Bytes())
+	return buffer.Bytes(), nil
+}
+
+// variant returns the variant with the given encoding or nil if it doesn't exist.
+func (file *staticFile) variant(encoding string) *staticVariant {
+	for i := range file.variants {
+		if file.variants[i].encoding == encoding {
+			return &file.variants[i]
+		}
+	}
+
+	return nil
+}
+
+// negotiate returns the variant with the highest quality in the Accept-Encoding
+// header, preferring smaller variants, or nil if no variant is accepted.
+func (file *staticFile) negotiate(acceptEncoding string) *staticVariant {
+	if acceptEncoding == "" || len(file.variants) == 0 {
+		return nil
+	}
+
+	encodings := parseAccept(acceptEncoding)
+	var best *staticVariant
+	bestQuality := 0.0
+
+	for i := range file.variants {
+		variant := &file.variants[i]
+		quality := encodingQuality(encodings, variant.encoding)
+
+		if quality > bestQuality || (quality > 0 && quality == bestQuality && variant.size < best.size) {
+			best = variant
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// isPrecompressed reports whether the file is a precompressed sibling.
+func isPrecompressed(name string) bool {
+	extension := path.Ext(name)
+
+	for _, sibling := range precompressed {
+		if extension == sibling.extension {
+			return true
+		}
+	}
+
+	return false
+}
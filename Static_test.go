@@ -0,0 +1,136 @@
+package aero_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aerogo/aero"
+	"github.com/akyoto/assert"
+)
+
+// staticRequest requests the path with the given Accept-Encoding header.
+func staticRequest(app *aero.Application, method string, path string, acceptEncoding string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	request.Header.Set("Accept-Encoding", acceptEncoding)
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	return response
+}
+
+func TestStatic(t *testing.T) {
+	script := strings.Repeat("console.log(42);\n", 100)
+	license := strings.Repeat("Permission is hereby granted, free of charge.\n", 2000)
+	gzipped := &bytes.Buffer{}
+	writer := gzip.NewWriter(gzipped)
+	_, err := writer.Write([]byte(script))
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	files := fstest.MapFS{
+		"app.js":                   {Data: []byte(script)},
+		"app.js.br":                {Data: []byte("brotli")},
+		"app.js.gz":                {Data: gzipped.Bytes()},
+		"images/logo.3f2a9c1d.svg": {Data: []byte("<svg></svg>")},
+		"LICENSE":                  {Data: []byte(license)},
+	}
+
+	app := aero.New()
+	app.StaticFS("/assets", files)
+	etag := strconv.Quote(aero.ETag([]byte(script)))
+
+	// Uncompressed
+	response := staticRequest(app, http.MethodGet, "/assets/app.js", "")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), script)
+	assert.Equal(t, response.Header().Get("ETag"), etag)
+	assert.Equal(t, response.Header().Get("Cache-Control"), "must-revalidate")
+	assert.Equal(t, response.Header().Get("Vary"), "Accept-Encoding")
+	assert.Contains(t, response.Header().Get("Content-Type"), "javascript")
+
+	// Smallest accepted sibling
+	response = staticRequest(app, http.MethodGet, "/assets/app.js", "gzip, br")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "br")
+	assert.Equal(t, response.Header().Get("ETag"), `"`+aero.ETag([]byte(script))+`-br"`)
+	assert.Equal(t, response.Body.String(), "brotli")
+
+	response = staticRequest(app, http.MethodGet, "/assets/app.js", "br;q=0.5, gzip")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "gzip")
+	assert.DeepEqual(t, response.Body.Bytes(), gzipped.Bytes())
+
+	// Conditional request
+	request := httptest.NewRequest(http.MethodGet, "/assets/app.js", nil)
+	request.Header.Set("If-None-Match", etag)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, http.StatusNotModified)
+
+	// HEAD
+	response = staticRequest(app, http.MethodHead, "/assets/app.js", "")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.Len(), 0)
+
+	// Fingerprinted file names
+	response = staticRequest(app, http.MethodGet, "/assets/images/logo.3f2a9c1d.svg", "gzip")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Cache-Control"), "public, max-age=31536000, immutable")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+	assert.Equal(t, response.Header().Get("Vary"), "")
+
+	// Large files without an extension
+	response = staticRequest(app, http.MethodGet, "/assets/LICENSE", "")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("ETag"), strconv.Quote(aero.ETag([]byte(license))))
+	assert.Equal(t, response.Header().Get("Content-Type"), "text/plain; charset=utf-8")
+
+	// Missing files
+	for _, path := range []string{"/assets/", "/assets/images", "/assets/missing.js", "/assets/../Static.go", "/assets/images/../../app.js.map"} {
+		response = staticRequest(app, http.MethodGet, path, "")
+		assert.Equal(t, response.Code, http.StatusNotFound)
+	}
+}
+
+func TestStaticPrecompress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aero-static-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	style := strings.Repeat("body { color: red; }\n", 100)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "style.css"), []byte(style), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "small.css"), []byte("a{}"), 0644))
+
+	app := aero.New()
+	app.Static("/static/", dir, aero.StaticOptions{Precompress: true})
+
+	response := staticRequest(app, http.MethodGet, "/static/style.css", "gzip")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "gzip")
+	assert.True(t, response.Body.Len() < len(style))
+	assert.Equal(t, gunzip(t, response.Body.Bytes()), style)
+
+	response = staticRequest(app, http.MethodGet, "/static/small.css", "gzip")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+	assert.Equal(t, response.Body.String(), "a{}")
+
+	// Modified files are reloaded
+	modified := strings.Repeat("body { color: blue; }\n", 100)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "style.css"), []byte(modified), 0644))
+
+	response = staticRequest(app, http.MethodGet, "/static/style.css", "gzip")
+	assert.Equal(t, gunzip(t, response.Body.Bytes()), modified)
+	assert.Equal(t, response.Header().Get("ETag"), `"`+aero.ETag([]byte(modified))+`-gzip"`)
+
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+
+	app.Static("/missing", filepath.Join(dir, "missing"), aero.StaticOptions{Precompress: true})
+}
@@ -0,0 +1,117 @@
+package aero
+
+import (
+	"io"
+	"net/http"
+)
+
+// StreamWriter writes a response body in chunks without buffering
+// the whole body in memory. Flush sends the data written so far
+// to the client and Close finishes the response body.
+type StreamWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// streamWriter writes to the response, compressed
+// if the response has a compressor.
+type streamWriter struct {
+	response   http.ResponseWriter
+	compressor *compressor
+	writer     CompressionWriter
+	closed     bool
+}
+
+// Stream sends the headers and returns a writer for a chunked response body.
+// The body is compressed if streaming compression is enabled in the
+// configuration and the client accepts one of the registered encodings.
+// Close must be called when the body is complete.
+func (ctx *context) Stream() (StreamWriter, error) {
+	header := ctx.response.inner.Header()
+	config := ctx.app.Config
+	stream := &streamWriter{
+		response: ctx.response.inner,
+	}
+
+	if config.GZip && config.Compression.Streaming && canCompress(header.Get(contentTypeHeader)) {
+		header.Add(varyHeader, acceptEncodingHeader)
+		stream.compressor = ctx.app.negotiateCompression(ctx.request.Header(acceptEncodingHeader))
+	}
+
+	if stream.compressor != nil {
+		writer, err := ctx.app.acquireCompressionWriter(stream.compressor, ctx.response.inner)
+
+		if err != nil {
+			return nil, err
+		}
+
+		stream.writer = writer
+		header.Set(contentEncodingHeader, stream.compressor.encoding)
+		header.Del(contentLengthHeader)
+	}
+
+	ctx.response.inner.WriteHeader(ctx.status)
+	return stream, nil
+}
+
+// Write writes the data to the response body.
+func (stream *streamWriter) Write(data []byte) (int, error) {
+	if stream.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if stream.writer == nil {
+		return stream.response.Write(data)
+	}
+
+	return stream.writer.Write(data)
+}
+
+// ReadFrom copies the reader to the response body.
+// Uncompressed responses keep the optimizations
+// of the underlying response writer.
+func (stream *streamWriter) ReadFrom(reader io.Reader) (int64, error) {
+	if stream.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if stream.writer == nil {
+		return io.Copy(stream.response, reader)
+	}
+
+	return io.Copy(stream.writer, reader)
+}
+
+// Flush sends the data written so far to the client.
+func (stream *streamWriter) Flush() error {
+	if stream.writer != nil {
+		err := stream.writer.Flush()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	flusher, ok := stream.response.(http.Flusher)
+
+	if ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// Close writes the remaining compressed data and
+// puts the compression writer back into the pool.
+func (stream *streamWriter) Close() error {
+	stream.closed = true
+
+	if stream.writer == nil {
+		return nil
+	}
+
+	err := stream.writer.Close()
+	stream.compressor.pool.Put(stream.writer)
+	stream.writer = nil
+	return err
+}
@@ -0,0 +1,116 @@
+package aero_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerogo/aero"
+	"github.com/aerogo/aero/event"
+	"github.com/akyoto/assert"
+)
+
+// gunzip decompresses the gzipped data.
+func gunzip(t *testing.T, data []byte) string {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	assert.Nil(t, err)
+	decompressed, err := ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	return string(decompressed)
+}
+
+func TestStreamReader(t *testing.T) {
+	app := aero.New()
+	body := strings.Repeat("id,name\n1,Alice\n", 1000)
+
+	app.Get("/", func(ctx aero.Context) error {
+		ctx.Response().SetHeader("Content-Type", "text/csv")
+		return ctx.Reader(strings.NewReader(body))
+	})
+
+	// Streaming compression is disabled by default
+	response := test(app, "/")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+	assert.Equal(t, response.Body.String(), body)
+
+	app.Config.Compression.Streaming = true
+	response = test(app, "/")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "gzip")
+	assert.Equal(t, response.Header().Get("Vary"), "Accept-Encoding")
+	assert.True(t, response.Body.Len() < len(body))
+	assert.Equal(t, gunzip(t, response.Body.Bytes()), body)
+}
+
+func TestStreamWriter(t *testing.T) {
+	app := aero.New()
+	app.Config.Compression.Streaming = true
+
+	app.Get("/", func(ctx aero.Context) error {
+		ctx.SetStatus(http.StatusAccepted)
+		writer, err := ctx.Stream()
+		assert.Nil(t, err)
+
+		_, err = io.WriteString(writer, "first chunk\n")
+		assert.Nil(t, err)
+		assert.Nil(t, writer.Flush())
+
+		// The flushed data can already be decompressed by the client
+		flushed := ctx.Response().Internal().(*httptest.ResponseRecorder)
+		assert.True(t, flushed.Flushed)
+		reader, err := gzip.NewReader(bytes.NewReader(flushed.Body.Bytes()))
+		assert.Nil(t, err)
+		partial := make([]byte, 12)
+		_, err = io.ReadFull(reader, partial)
+		assert.Nil(t, err)
+		assert.Equal(t, string(partial), "first chunk\n")
+
+		_, err = io.WriteString(writer, "second chunk\n")
+		assert.Nil(t, err)
+		assert.Nil(t, writer.Close())
+
+		_, err = io.WriteString(writer, "too late")
+		assert.Equal(t, err, io.ErrClosedPipe)
+		return nil
+	})
+
+	response := test(app, "/")
+	assert.Equal(t, response.Code, http.StatusAccepted)
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "gzip")
+	assert.Equal(t, gunzip(t, response.Body.Bytes()), "first chunk\nsecond chunk\n")
+}
+
+func TestStreamEventStream(t *testing.T) {
+	app := aero.New()
+	app.Config.Compression.Streaming = true
+
+	app.Get("/", func(ctx aero.Context) error {
+		stream := event.NewStream()
+
+		go func() {
+			stream.Events <- event.New("ping", "{}")
+			<-stream.Closed
+		}()
+
+		return ctx.EventStream(stream)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request.WithContext(ctx))
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "gzip")
+	assert.Equal(t, gunzip(t, response.Body.Bytes()), "event: ping\ndata: {}\n\n")
+}
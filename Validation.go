@@ -0,0 +1,360 @@
+package aero
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidationError is returned when the input doesn't satisfy
+// the validation rules. Handlers usually respond to it
+// with 422 Unprocessable Entity.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// FieldError describes a single field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error returns a summary of all invalid fields.
+func (err *ValidationError) Error() string {
+	messages := make([]string, 0, len(err.Fields))
+
+	for _, field := range err.Fields {
+		messages = append(messages, field.Field+" "+field.Message)
+	}
+
+	return "Validation failed: " + strings.Join(messages, ", ")
+}
+
+// add adds an invalid field.
+func (err *ValidationError) add(field string, rule string, param string, message string) {
+	err.Fields = append(err.Fields, FieldError{
+		Field:   field,
+		Rule:    rule,
+		Param:   param,
+		Message: message,
+	})
+}
+
+// has reports whether the field has already been marked as invalid.
+func (err *ValidationError) has(field string) bool {
+	for _, invalid := range err.Fields {
+		if invalid.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks the struct, or pointer to a struct, against the rules
+// declared in its `validate` tags. Supported rules are required, min, max,
+// email and oneof, e.g. `validate:"required,min=3,max=20"`.
+// If a rule is violated, the returned error is a *ValidationError.
+func Validate(data interface{}) error {
+	value := reflect.ValueOf(data)
+
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+
+	invalid := &ValidationError{}
+	validateStruct(value, "", invalid)
+
+	if len(invalid.Fields) > 0 {
+		return invalid
+	}
+
+	return nil
+}
+
+// rule is a single validation rule like min=3.
+type rule struct {
+	name  string
+	param string
+	limit float64
+}
+
+// field describes a struct field that takes part in binding or validation.
+type field struct {
+	index  []int
+	name   string
+	form   string
+	query  string
+	param  string
+	rules  []rule
+	nested bool
+}
+
+// structFields caches the fields of each struct type.
+var structFields sync.Map
+
+// fieldsOf returns the fields of the struct type including
+// the fields of embedded structs.
+func fieldsOf(structType reflect.Type) []field {
+	cached, found := structFields.Load(structType)
+
+	if found {
+		return cached.([]field)
+	}
+
+	fields := appendFields(nil, structType, nil)
+	structFields.Store(structType, fields)
+	return fields
+}
+
+// appendFields appends the exported fields of the struct type to the list.
+func appendFields(fields []field, structType reflect.Type, index []int) []field {
+	for i := 0; i < structType.NumField(); i++ {
+		definition := structType.Field(i)
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		if definition.Anonymous && definition.Type.Kind() == reflect.Struct {
+			fields = appendFields(fields, definition.Type, fieldIndex)
+			continue
+		}
+
+		if definition.PkgPath != "" {
+			continue
+		}
+
+		info := field{
+			index:  fieldIndex,
+			name:   definition.Name,
+			form:   tagName(definition.Tag.Get("form")),
+			query:  tagName(definition.Tag.Get("query")),
+			param:  tagName(definition.Tag.Get("param")),
+			rules:  parseRules(definition),
+			nested: isNestedStruct(definition.Type),
+		}
+
+		for _, name := range []string{tagName(definition.Tag.Get("json")), info.form, info.query, info.param} {
+			if name != "" {
+				info.name = name
+				break
+			}
+		}
+
+		fields = append(fields, info)
+	}
+
+	return fields
+}
+
+// tagName returns the name part of a struct tag like `json:"name,omitempty"`.
+func tagName(tag string) string {
+	name := strings.SplitN(tag, ",", 2)[0]
+
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// isNestedStruct reports whether the type is a struct whose fields
+// should be validated as well.
+func isNestedStruct(fieldType reflect.Type) bool {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType.Kind() == reflect.Struct && !reflect.PtrTo(fieldType).Implements(textUnmarshalerType)
+}
+
+// parseRules parses the validation rules of the struct field.
+// It panics on unknown rules or invalid parameters.
+func parseRules(definition reflect.StructField) []rule {
+	tag := definition.Tag.Get("validate")
+
+	if tag == "" {
+		return nil
+	}
+
+	var rules []rule
+
+	for _, part := range strings.Split(tag, ",") {
+		parsed := rule{}
+		parts := strings.SplitN(part, "=", 2)
+		parsed.name = strings.TrimSpace(parts[0])
+
+		if len(parts) == 2 {
+			parsed.param = parts[1]
+		}
+
+		switch parsed.name {
+		case "required", "email", "oneof":
+
+		case "min", "max":
+			limit, err := strconv.ParseFloat(parsed.param, 64)
+
+			if err != nil {
+				panic(fmt.Errorf("Invalid parameter '%s' for validation rule '%s' on field %s", parsed.param, parsed.name, definition.Name))
+			}
+
+			if !isMeasurable(definition.Type) {
+				panic(fmt.Errorf("Validation rule '%s' is not supported for type %s of field %s", parsed.name, definition.Type, definition.Name))
+			}
+
+			parsed.limit = limit
+
+		default:
+			panic(fmt.Errorf("Unknown validation rule '%s' on field %s", parsed.name, definition.Name))
+		}
+
+		rules = append(rules, parsed)
+	}
+
+	return rules
+}
+
+// validateStruct checks all fields of the struct against their rules.
+// Fields that are already invalid are skipped.
+func validateStruct(value reflect.Value, prefix string, invalid *ValidationError) {
+	for _, field := range fieldsOf(value.Type()) {
+		name := prefix + field.name
+
+		if invalid.has(name) {
+			continue
+		}
+
+		fieldValue := value.FieldByIndex(field.index)
+
+		for _, rule := range field.rules {
+			message := rule.check(fieldValue)
+
+			if message != "" {
+				invalid.add(name, rule.name, rule.param, message)
+				break
+			}
+		}
+
+		if field.nested {
+			for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+
+			if fieldValue.Kind() == reflect.Struct {
+				validateStruct(fieldValue, name+".", invalid)
+			}
+		}
+	}
+}
+
+// check returns an error message if the value violates the rule.
+// Rules other than required are not checked for nil pointers
+// and empty strings, slices and maps.
+func (rule *rule) check(value reflect.Value) string {
+	if rule.name == "required" {
+		if value.IsZero() {
+			return "is required"
+		}
+
+		return ""
+	}
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		if value.Len() == 0 {
+			return ""
+		}
+	}
+
+	switch rule.name {
+	case "min":
+		size, unit := measure(value)
+
+		if size < rule.limit {
+			return "must be at least " + rule.param + unit
+		}
+
+	case "max":
+		size, unit := measure(value)
+
+		if size > rule.limit {
+			return "must be at most " + rule.param + unit
+		}
+
+	case "email":
+		text := fmt.Sprint(value.Interface())
+		address, err := mail.ParseAddress(text)
+
+		if err != nil || address.Address != text {
+			return "must be a valid email address"
+		}
+
+	case "oneof":
+		text := fmt.Sprint(value.Interface())
+		options := strings.Fields(rule.param)
+
+		for _, option := range options {
+			if option == text {
+				return ""
+			}
+		}
+
+		return "must be one of: " + strings.Join(options, ", ")
+	}
+
+	return ""
+}
+
+// isMeasurable reports whether the min and max rules can be applied to the type.
+func isMeasurable(fieldType reflect.Type) bool {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// measure returns the size of the value used by the min and max rules:
+// the number of characters of strings, the number of elements of
+// slices and maps and the value itself for numbers.
+// Other types are rejected by parseRules.
+func measure(value reflect.Value) (float64, string) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters long"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), ""
+	default:
+		return 0, ""
+	}
+}
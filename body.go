@@ -0,0 +1,154 @@
+package aero
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// limitBody restricts the size of the request body to the limit of the
+// matched route or the configured default and transparently decodes
+// gzip and deflate encoded bodies. Requests that declare a larger
+// Content-Length are rejected before the handler is called.
+// Bodies with an unknown Content-Encoding fail when they're read.
+func (app *Application) limitBody(request *http.Request, ctx *context) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return
+	}
+
+	limit := app.Config.Limits.Body
+	decodedLimit := app.Config.Limits.DecodedBody
+
+	if ctx.route != nil && ctx.route.hasLimit {
+		limit = ctx.route.bodyLimit
+		decodedLimit = ctx.route.bodyLimit
+	}
+
+	if limit > 0 && request.ContentLength > limit {
+		ctx.handler = rejectBody(http.StatusRequestEntityTooLarge, ErrBodyTooLarge)
+		return
+	}
+
+	var body io.ReadCloser = request.Body
+
+	if limit > 0 {
+		body = &limitedBody{
+			limitedReader: limitedReader{
+				reader:    request.Body,
+				remaining: limit,
+				err:       ErrBodyTooLarge,
+			},
+			Closer: request.Body,
+		}
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(request.Header.Get(contentEncodingHeader)))
+
+	switch encoding {
+	case "", "identity":
+		request.Body = body
+		return
+
+	case contentEncodingGzip, "x-gzip", contentEncodingDeflate:
+		request.Body = &decodedBody{
+			body:     body,
+			encoding: encoding,
+			limit:    decodedLimit,
+		}
+
+		request.Header.Del(contentEncodingHeader)
+		request.Header.Del(contentLengthHeader)
+		request.ContentLength = -1
+
+	default:
+		request.Body = &decodedBody{
+			body:     body,
+			encoding: encoding,
+			err:      fmt.Errorf("%w: %s", ErrUnsupportedContentEncoding, encoding),
+		}
+	}
+}
+
+// rejectBody returns a handler that responds with the given error.
+func rejectBody(statusCode int, err error) Handler {
+	return func(ctx Context) error {
+		ctx.Response().SetHeader(connectionHeader, "close")
+		return ctx.Error(statusCode, err)
+	}
+}
+
+// limitedBody is a request body that fails
+// with ErrBodyTooLarge once the limit is exceeded.
+type limitedBody struct {
+	limitedReader
+	io.Closer
+}
+
+// decodedBody decompresses a request body. The decoder is created on
+// the first read so that invalid bodies only fail when they're used.
+// Bodies with an unsupported encoding start out with the error.
+type decodedBody struct {
+	body     io.ReadCloser
+	encoding string
+	limit    int64
+	decoder  io.ReadCloser
+	reader   io.Reader
+	err      error
+}
+
+// Read implements the io.Reader interface.
+func (body *decodedBody) Read(buffer []byte) (int, error) {
+	if body.reader == nil && body.err == nil {
+		body.init()
+	}
+
+	if body.err != nil {
+		return 0, body.err
+	}
+
+	return body.reader.Read(buffer)
+}
+
+// init creates the decoder for the content encoding.
+func (body *decodedBody) init() {
+	var decoder io.ReadCloser
+	var err error
+
+	if body.encoding == contentEncodingDeflate {
+		decoder, err = zlib.NewReader(body.body)
+	} else {
+		decoder, err = gzip.NewReader(body.body)
+	}
+
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	if err != nil {
+		body.err = err
+		return
+	}
+
+	body.decoder = decoder
+	body.reader = decoder
+
+	if body.limit > 0 {
+		body.reader = &limitedReader{
+			reader:    body.decoder,
+			remaining: body.limit,
+			err:       ErrBodyTooLarge,
+		}
+	}
+}
+
+// Close closes the decoder and the original request body.
+func (body *decodedBody) Close() error {
+	if body.decoder != nil {
+		_ = body.decoder.Close()
+	}
+
+	return body.body.Close()
+}
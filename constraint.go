@@ -0,0 +1,113 @@
+package aero
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// constraint restricts the values a route parameter accepts.
+type constraint struct {
+	source string
+	match  func(string) bool
+}
+
+// constraintExpressions contains the regular expressions
+// of the known constraint types for use in patterns.
+var constraintExpressions = map[string]string{
+	"int":  "-?[0-9]+",
+	"uuid": "[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}",
+}
+
+// constraintSamples contains values accepted by the known constraint types.
+// Other constraints matching any of them at the same position are ambiguous.
+var constraintSamples = map[string][]string{
+	"int":  {"0", "42", "-1"},
+	"uuid": {"6ba7b810-9dad-11d1-80b4-00c04fd430c8", "f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+}
+
+// newConstraint creates a constraint from the given source.
+// Known types are int and uuid, everything else is treated
+// as a regular expression that must match the entire value.
+func newConstraint(source string) *constraint {
+	switch source {
+	case "int":
+		return &constraint{source: source, match: isInt}
+	case "uuid":
+		return &constraint{source: source, match: isUUID}
+	}
+
+	expression, err := regexp.Compile("^(?:" + source + ")$")
+
+	if err != nil {
+		panic(fmt.Errorf("Invalid parameter constraint '%s': %w", source, err))
+	}
+
+	return &constraint{source: source, match: expression.MatchString}
+}
+
+// isInt reports whether the value is a decimal integer.
+func isInt(value string) bool {
+	if value != "" && value[0] == '-' {
+		value = value[1:]
+	}
+
+	if value == "" {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isUUID reports whether the value is a UUID in its canonical textual form.
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+
+		switch i {
+		case 8, 13, 18, 23:
+			if char != '-' {
+				return false
+			}
+
+		default:
+			if (char < '0' || char > '9') && (char < 'a' || char > 'f') && (char < 'A' || char > 'F') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// overlaps reports whether both constraints accept the same values.
+// Regular expressions can only be compared with the known types,
+// two different regular expressions are never reported as overlapping.
+func (a *constraint) overlaps(b *constraint) bool {
+	if a.source == b.source {
+		return true
+	}
+
+	for _, sample := range constraintSamples[a.source] {
+		if b.match(sample) {
+			return true
+		}
+	}
+
+	for _, sample := range constraintSamples[b.source] {
+		if a.match(sample) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,7 +4,18 @@ import "errors"
 
 var (
 	ErrAddressNotValid            = errors.New("Address is not valid")
+	ErrBodyTooLarge               = errors.New("Request body too large")
 	ErrEmptyBody                  = errors.New("Empty body")
 	ErrExpectedJSONObject         = errors.New("Invalid format: Expected JSON object")
+	ErrInvalidBindTarget          = errors.New("Bind target must be a pointer to a struct")
+	ErrInvalidParameters          = errors.New("Parameters must be name and value pairs")
+	ErrJSONTooDeep                = errors.New("JSON nesting too deep")
+	ErrMissingParameter           = errors.New("Missing route parameter")
+	ErrNotMultipart               = errors.New("Request body is not multipart")
+	ErrPartTooLarge               = errors.New("Multipart part too large")
+	ErrPreconditionFailed         = errors.New("Precondition failed")
 	ErrRequestInterruptedByClient = errors.New("Request interrupted by the client")
+	ErrUnknownRoute               = errors.New("Unknown route")
+	ErrUnsupportedContentEncoding = errors.New("Unsupported content encoding")
+	ErrUnsupportedContentType     = errors.New("Unsupported content type")
 )
@@ -1,6 +1,6 @@
 module github.com/aerogo/aero
 
-go 1.12
+go 1.18
 
 require (
 	github.com/aerogo/csp v0.1.10
@@ -11,4 +11,18 @@ require (
 	github.com/akyoto/color v1.8.12
 	github.com/akyoto/hash v0.5.0
 	github.com/akyoto/stringutils v0.3.1
+	github.com/akyoto/uuid v1.1.3
+	github.com/andybalholm/brotli v1.1.1
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	github.com/zeebo/xxh3 v1.0.1
+)
+
+require (
+	github.com/akyoto/colorable v0.1.7 // indirect
+	github.com/akyoto/tty v0.1.4 // indirect
+	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/sys v0.0.0-20211117180635-dee7805ff2e1 // indirect
 )
@@ -0,0 +1,167 @@
+package aero
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// find looks up the route for the given method and path.
+// HEAD requests fall back to the GET route of the same path.
+// The parameters are reset if no route was found.
+func find(router *Router, method string, path string, ctx *context) {
+	params := ctx.paramCount
+	router.Lookup(method, path, ctx)
+
+	if ctx.route == nil && method == http.MethodHead {
+		ctx.paramCount = params
+		router.Lookup(http.MethodGet, path, ctx)
+	}
+
+	if ctx.route == nil {
+		ctx.paramCount = params
+	}
+}
+
+// redirect responds with a permanent redirect to the given path
+// and keeps the query string. Methods other than GET and HEAD
+// use 308 so that the client repeats the request with the same method.
+func redirect(location string, request *http.Request, ctx *context) {
+	if request.URL.RawQuery != "" {
+		location += "?" + request.URL.RawQuery
+	}
+
+	ctx.status = http.StatusPermanentRedirect
+
+	if request.Method == http.MethodGet || request.Method == http.MethodHead {
+		ctx.status = http.StatusMovedPermanently
+	}
+
+	ctx.response.SetHeader(locationHeader, location)
+	ctx.route = nil
+	ctx.handler = emptyResponse
+}
+
+// cleanPath removes duplicate slashes as well as . and .. segments
+// from the path while keeping the trailing slash.
+func cleanPath(p string) string {
+	if isCleanPath(p) {
+		return p
+	}
+
+	cleaned := path.Clean("/" + p)
+
+	if cleaned != "/" && strings.HasSuffix(p, "/") {
+		cleaned += "/"
+	}
+
+	return cleaned
+}
+
+// isCleanPath reports whether the path is absolute and contains
+// no duplicate slashes, . or .. segments.
+func isCleanPath(p string) bool {
+	if p == "" || p[0] != separator {
+		return false
+	}
+
+	for i := 1; i < len(p); i++ {
+		if p[i-1] != separator {
+			continue
+		}
+
+		switch {
+		case p[i] == separator:
+			return false
+
+		case p[i] == '.':
+			rest := p[i+1:]
+
+			if rest == "" || rest[0] == separator || rest == "." || strings.HasPrefix(rest, "./") {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// toggleTrailingSlash adds a trailing slash to the path
+// or removes it if the path already has one.
+func toggleTrailingSlash(path string) string {
+	if path == "/" {
+		return path
+	}
+
+	if strings.HasSuffix(path, "/") {
+		return path[:len(path)-1]
+	}
+
+	return path + "/"
+}
+
+// toLowerASCII converts the ASCII letters in the path to lower case.
+// Unlike strings.ToLower, it keeps the length of the path so that
+// parts of the original path can be found at the same offsets.
+func toLowerASCII(path string) string {
+	for i := 0; i < len(path); i++ {
+		if path[i] < 'A' || path[i] > 'Z' {
+			continue
+		}
+
+		lowered := []byte(path)
+
+		for j := i; j < len(lowered); j++ {
+			if lowered[j] >= 'A' && lowered[j] <= 'Z' {
+				lowered[j] += 'a' - 'A'
+			}
+		}
+
+		return string(lowered)
+	}
+
+	return path
+}
+
+// keepParameterCase returns the lowered path with the values of the route
+// parameters restored from the original path, so that only the static parts
+// of the route are lowered. The parameters of the route start at params.
+func keepParameterCase(lowered string, original string, pattern string, params int, ctx *context) string {
+	restored := []byte(lowered)
+	offset := 0
+
+	for i := params; i < ctx.paramCount; i++ {
+		static, _, rest := nextToken(pattern)
+		value := ctx.paramValue(i)
+		offset += len(static)
+
+		if offset+len(value) > len(original) {
+			break
+		}
+
+		copy(restored[offset:], original[offset:offset+len(value)])
+		offset += len(value)
+		pattern = rest
+	}
+
+	return string(restored)
+}
+
+// canonicalTrailingSlash returns the path with the trailing slash
+// of the route pattern. Paths of wildcard routes are not modified.
+func canonicalTrailingSlash(path string, pattern string) string {
+	if strings.IndexByte(pattern, wildcard) != -1 || path == "/" {
+		return path
+	}
+
+	hasSlash := strings.HasSuffix(path, "/")
+
+	switch {
+	case strings.HasSuffix(pattern, "/") && !hasSlash:
+		return path + "/"
+	case !strings.HasSuffix(pattern, "/") && hasSlash:
+		return path[:len(path)-1]
+	default:
+		return path
+	}
+}
@@ -0,0 +1,313 @@
+package aero
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// token is a parameter or wildcard within a route path,
+// e.g. :id<int>, :month? or *file.
+type token struct {
+	kind       byte
+	name       string
+	constraint string
+	optional   bool
+}
+
+// pattern matches path segments or wildcard values that contain
+// static text besides the parameters, e.g. :name.:ext or *path.zip.
+type pattern struct {
+	expression *regexp.Regexp
+	names      []string
+	groups     []int
+}
+
+// nextToken splits the path at the next parameter or wildcard.
+// It returns the static text in front of the token, the token
+// and the remaining path. The token kind is 0 if there is none.
+func nextToken(path string) (string, token, string) {
+	start := strings.IndexAny(path, ":*")
+
+	if start == -1 {
+		return path, token{}, ""
+	}
+
+	static := path[:start]
+	definition := path[start:]
+	next := token{kind: path[start]}
+	path = path[start+1:]
+	end := 0
+
+	for end < len(path) && isNameChar(path[end]) {
+		end++
+	}
+
+	next.name = path[:end]
+	path = path[end:]
+
+	if path != "" && path[0] == '<' {
+		end = constraintEnd(path)
+
+		if end <= 1 {
+			panic(fmt.Errorf("Invalid parameter constraint: '%s'", definition))
+		}
+
+		next.constraint = path[1:end]
+		path = path[end+1:]
+	}
+
+	if next.kind == parameter && path != "" && path[0] == '?' {
+		next.optional = true
+		path = path[1:]
+	}
+
+	return static, next, path
+}
+
+// constraintEnd returns the index of the > closing the constraint
+// at the start of the path or -1 if the constraint is not closed.
+func constraintEnd(path string) int {
+	depth := 0
+
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '<':
+			depth++
+
+		case '>':
+			depth--
+
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// segmentEnd returns the index of the slash ending the first segment
+// of the route path or -1 if the path consists of a single segment.
+// Slashes within parameter constraints don't end the segment.
+func segmentEnd(path string) int {
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case separator:
+			return i
+
+		case parameter, wildcard:
+			i++
+
+			for i < len(path) && isNameChar(path[i]) {
+				i++
+			}
+
+			if i < len(path) && path[i] == '<' {
+				end := constraintEnd(path[i:])
+
+				if end == -1 {
+					return -1
+				}
+
+				i += end
+			} else {
+				i--
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitSegments splits the route path at the slashes
+// that are not part of a parameter constraint.
+func splitSegments(path string) []string {
+	segments := []string{}
+
+	for {
+		end := segmentEnd(path)
+
+		if end == -1 {
+			return append(segments, path)
+		}
+
+		segments = append(segments, path[:end])
+		path = path[end+1:]
+	}
+}
+
+// isNameChar reports whether the character can be part of a parameter name.
+func isNameChar(char byte) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || char == '_'
+}
+
+// isPattern reports whether the definition of a parameter or wildcard
+// contains more than a single token, e.g. :name.:ext or *path.zip.
+func isPattern(definition string) bool {
+	_, _, rest := nextToken(definition)
+	return rest != ""
+}
+
+// segmentShape returns the segment with all parameter names removed.
+// Segments with the same shape match the same values.
+func segmentShape(segment string) string {
+	shape := strings.Builder{}
+
+	for {
+		static, next, rest := nextToken(segment)
+		shape.WriteString(static)
+
+		if next.kind == 0 {
+			return shape.String()
+		}
+
+		shape.WriteByte(next.kind)
+
+		if next.constraint != "" {
+			shape.WriteString("<" + next.constraint + ">")
+		}
+
+		if next.optional {
+			shape.WriteByte('?')
+		}
+
+		segment = rest
+	}
+}
+
+// expandOptional returns the paths a route with optional parameters
+// responds to, e.g. /archive/:year/:month? expands to
+// /archive/:year/:month and /archive/:year.
+// Optional parameters must be full segments at the end of the path.
+func expandOptional(path string) []string {
+	if strings.IndexByte(path, '?') == -1 {
+		return []string{path}
+	}
+
+	segments := splitSegments(path)
+	required := len(segments)
+
+	for required > 0 && isOptional(segments[required-1]) {
+		required--
+		segments[required] = segments[required][:len(segments[required])-1]
+	}
+
+	for _, segment := range segments[:required] {
+		for segment != "" {
+			_, next, rest := nextToken(segment)
+
+			if next.optional {
+				panic(fmt.Errorf("Optional parameter '%s' must be at the end of the path %s", next.name, path))
+			}
+
+			segment = rest
+		}
+	}
+
+	paths := make([]string, 0, len(segments)-required+1)
+
+	for count := len(segments); count >= required; count-- {
+		expanded := strings.Join(segments[:count], "/")
+
+		if expanded == "" {
+			expanded = "/"
+		}
+
+		paths = append(paths, expanded)
+	}
+
+	return paths
+}
+
+// isOptional reports whether the segment is a single optional parameter.
+func isOptional(segment string) bool {
+	static, next, rest := nextToken(segment)
+	return static == "" && next.optional && rest == ""
+}
+
+// newPattern compiles the definition of a parameter or wildcard
+// consisting of multiple tokens into a pattern.
+// Parameters match as much as possible within a segment,
+// wildcards match any text including slashes.
+func newPattern(definition string) *pattern {
+	expression := strings.Builder{}
+	expression.WriteString("^")
+	names := []string{}
+	remaining := definition
+
+	for remaining != "" {
+		static, next, rest := nextToken(remaining)
+		expression.WriteString(regexp.QuoteMeta(static))
+
+		if next.kind == 0 {
+			break
+		}
+
+		expression.WriteString("(?P<p" + strconv.Itoa(len(names)) + ">" + tokenExpression(next) + ")")
+		names = append(names, next.name)
+		remaining = rest
+	}
+
+	expression.WriteString("$")
+	compiled, err := regexp.Compile(expression.String())
+
+	if err != nil {
+		panic(fmt.Errorf("Invalid parameter constraint '%s': %w", definition, err))
+	}
+
+	groups := make([]int, len(names))
+
+	for index, name := range compiled.SubexpNames() {
+		if strings.HasPrefix(name, "p") {
+			group, err := strconv.Atoi(name[1:])
+
+			if err == nil && group < len(groups) {
+				groups[group] = index
+			}
+		}
+	}
+
+	return &pattern{
+		expression: compiled,
+		names:      names,
+		groups:     groups,
+	}
+}
+
+// tokenExpression returns the regular expression matching the values of the token.
+func tokenExpression(next token) string {
+	if next.constraint == "" {
+		if next.kind == wildcard {
+			return "(?s:.+)"
+		}
+
+		return "[^/]+"
+	}
+
+	known, found := constraintExpressions[next.constraint]
+
+	if found {
+		return known
+	}
+
+	return "(?:" + next.constraint + ")"
+}
+
+// match reports whether the value matches the pattern
+// and adds the parameters to the context.
+func (pattern *pattern) match(value string, ctx *context) bool {
+	matches := pattern.expression.FindStringSubmatchIndex(value)
+
+	if matches == nil {
+		return false
+	}
+
+	for index, name := range pattern.names {
+		group := pattern.groups[index]
+		ctx.addParameter(name, value[matches[2*group]:matches[2*group+1]])
+	}
+
+	return true
+}
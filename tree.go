@@ -15,7 +15,7 @@ const (
 )
 
 // dataType specifies which type of data we are going to save for each node.
-type dataType = Handler
+type dataType = *Route
 
 // tree represents a radix tree.
 type tree struct {
@@ -45,6 +45,18 @@ func (tree *tree) add(path string, data dataType) {
 	begin:
 		switch node.kind {
 		case parameter:
+			// Skip the parameter definition as a whole,
+			// its constraint may contain slashes.
+			if i == offset {
+				end := segmentEnd(path[i:])
+
+				if end == -1 {
+					end = len(path) - i
+				}
+
+				i += end
+			}
+
 			// This only occurs when the same parameter based route is added twice.
 			// node: /post/:id|
 			// path: /post/:id|
@@ -116,26 +128,30 @@ func (tree *tree) add(path string, data dataType) {
 	}
 }
 
-// find finds the data for the given path and assigns it to ctx.handler, if available.
+// find finds the data for the given path and assigns it to ctx.route, if available.
 func (tree *tree) find(path string, ctx *context) {
 	if tree.canBeStatic[len(path)] {
-		handler, found := tree.static[path]
+		route, found := tree.static[path]
 
 		if found {
-			ctx.handler = handler
+			ctx.route = route
 			return
 		}
 	}
 
+	ctx.route = search(&tree.root, path, 0, 0, ctx)
+}
+
+// search returns the data for the path below the node or nil if there is none.
+// The node prefix starts at the given offset, i is the index in the path
+// where the comparison continues.
+func search(node *treeNode, path string, i uint, offset uint, ctx *context) dataType {
 	var (
-		i                  uint
-		offset             uint
 		lastWildcardOffset uint
+		lastWildcardParams int
 		lastWildcard       *treeNode
-		node               = &tree.root
 	)
 
-begin:
 	// Search tree for equal parts until we can no longer proceed
 	for {
 		// We reached the end.
@@ -143,23 +159,29 @@ begin:
 			// node: /blog|
 			// path: /blog|
 			if i-offset == uint(len(node.prefix)) {
-				ctx.handler = node.data
-				return
+				// node: /blog/|*any
+				// path: /blog/|
+				if node.data == nil && node.wildcard != nil {
+					ctx.addParameter(node.wildcard.prefix, "")
+					return node.wildcard.data
+				}
+
+				return node.data
 			}
 
 			// node: /blog|feed
 			// path: /blog|
-			ctx.handler = nil
-			return
+			return nil
 		}
 
 		// The node we just checked is entirely included in our path.
 		// node: /|
 		// path: /|blog
 		if i-offset == uint(len(node.prefix)) {
-			if node.wildcard != nil {
-				lastWildcard = node.wildcard
+			if node.wildcard != nil || node.wildcards != nil {
+				lastWildcard = node
 				lastWildcardOffset = i
+				lastWildcardParams = ctx.paramCount
 			}
 
 			char := path[i]
@@ -177,73 +199,32 @@ begin:
 
 			// node: /|:id
 			// path: /|blog
-			if node.parameter != nil {
-				node = node.parameter
-				offset = i
-				i++
-
-				for {
-					// We reached the end.
-					if i == uint(len(path)) {
-						ctx.addParameter(node.prefix, path[offset:i])
-						ctx.handler = node.data
-						return
-					}
-
-					// node: /:id|/posts
-					// path: /123|/posts
-					if path[i] == separator {
-						ctx.addParameter(node.prefix, path[offset:i])
-						index := node.indices[separator-node.startIndex]
-						node = node.children[index]
-						offset = i
-						i++
-						goto begin
-					}
+			if node.parameter != nil || node.constrained != nil {
+				end := i
 
-					i++
+				for end < uint(len(path)) && path[end] != separator {
+					end++
+				}
+
+				data := node.searchParameters(path, i, end, ctx)
+
+				if data != nil {
+					return data
 				}
 			}
 
 			// node: /|*any
 			// path: /|image.png
-			if node.wildcard != nil {
-				ctx.addParameter(node.wildcard.prefix, path[i:])
-				ctx.handler = node.wildcard.data
-				return
-			}
-
-			ctx.handler = nil
-			return
+			return lastWildcard.searchWildcard(path[lastWildcardOffset:], lastWildcardParams, ctx)
 		}
 
 		// We got a conflict.
 		// node: /b|ag
 		// path: /b|riefcase
 		if path[i] != node.prefix[i-offset] {
-			if lastWildcard != nil {
-				ctx.addParameter(lastWildcard.prefix, path[lastWildcardOffset:])
-				ctx.handler = lastWildcard.data
-				return
-			}
-
-			ctx.handler = nil
-			return
+			return lastWildcard.searchWildcard(path[lastWildcardOffset:], lastWildcardParams, ctx)
 		}
 
 		i++
 	}
 }
-
-// bind binds all handlers to a new one provided by the callback.
-func (tree *tree) bind(transform func(Handler) Handler) {
-	tree.root.each(func(node *treeNode) {
-		if node.data != nil {
-			node.data = transform(node.data)
-		}
-	})
-
-	for key, value := range tree.static {
-		tree.static[key] = transform(value)
-	}
-}
@@ -17,15 +17,19 @@ const (
 
 // treeNode represents a radix tree node.
 type treeNode struct {
-	startIndex uint8
-	endIndex   uint8
-	kind       byte
-	prefix     string
-	indices    []uint8
-	children   []*treeNode
-	data       dataType
-	parameter  *treeNode
-	wildcard   *treeNode
+	startIndex  uint8
+	endIndex    uint8
+	kind        byte
+	prefix      string
+	indices     []uint8
+	children    []*treeNode
+	data        dataType
+	parameter   *treeNode
+	wildcard    *treeNode
+	wildcards   []*treeNode
+	constraint  *constraint
+	pattern     *pattern
+	constrained []*treeNode
 }
 
 // split splits the node at the given index and inserts
@@ -56,15 +60,19 @@ func (node *treeNode) split(index int, path string, data dataType) {
 // clone clones the node with a new prefix.
 func (node *treeNode) clone(prefix string) *treeNode {
 	return &treeNode{
-		prefix:     prefix,
-		data:       node.data,
-		indices:    node.indices,
-		startIndex: node.startIndex,
-		endIndex:   node.endIndex,
-		children:   node.children,
-		parameter:  node.parameter,
-		wildcard:   node.wildcard,
-		kind:       node.kind,
+		prefix:      prefix,
+		data:        node.data,
+		indices:     node.indices,
+		startIndex:  node.startIndex,
+		endIndex:    node.endIndex,
+		children:    node.children,
+		parameter:   node.parameter,
+		wildcard:    node.wildcard,
+		wildcards:   node.wildcards,
+		constraint:  node.constraint,
+		pattern:     node.pattern,
+		constrained: node.constrained,
+		kind:        node.kind,
 	}
 }
 
@@ -74,6 +82,10 @@ func (node *treeNode) reset(prefix string) {
 	node.data = nil
 	node.parameter = nil
 	node.wildcard = nil
+	node.wildcards = nil
+	node.constraint = nil
+	node.pattern = nil
+	node.constrained = nil
 	node.kind = 0
 	node.startIndex = 0
 	node.endIndex = 0
@@ -146,11 +158,7 @@ func (node *treeNode) append(path string, data dataType) {
 			return
 		}
 
-		paramStart := strings.IndexByte(path, parameter)
-
-		if paramStart == -1 {
-			paramStart = strings.IndexByte(path, wildcard)
-		}
+		paramStart := strings.IndexAny(path, ":*")
 
 		// If it's a static route we are adding,
 		// just add the remainder as a normal node.
@@ -176,28 +184,52 @@ func (node *treeNode) append(path string, data dataType) {
 		// If we're directly in front of a parameter,
 		// add a parameter node.
 		if paramStart == 0 {
-			paramEnd := strings.IndexByte(path, separator)
+			paramEnd := segmentEnd(path)
 
-			if paramEnd == -1 {
+			if paramEnd == -1 || path[0] == wildcard {
 				paramEnd = len(path)
 			}
 
+			definition := path[:paramEnd]
+			_, next, _ := nextToken(definition)
+
 			child := &treeNode{
-				prefix: path[1:paramEnd],
-				kind:   path[paramStart],
+				prefix: next.name,
+				kind:   next.kind,
+			}
+
+			// Wildcard constraints are matched like patterns
+			// because wildcard values span multiple segments.
+			if isPattern(definition) || (next.kind == wildcard && next.constraint != "") {
+				child.prefix = definition[1:]
+				child.pattern = newPattern(definition)
+			} else if next.constraint != "" {
+				child.constraint = newConstraint(next.constraint)
 			}
 
 			switch child.kind {
 			case parameter:
 				child.addTrailingSlash(data)
-				node.parameter = child
+
+				if child.pattern != nil || child.constraint != nil {
+					node.constrained = append(node.constrained, child)
+				} else {
+					node.parameter = child
+				}
+
 				node = child
 				path = path[paramEnd:]
 				continue
 
 			case wildcard:
 				child.data = data
-				node.wildcard = child
+
+				if child.pattern != nil {
+					node.wildcards = append(node.wildcards, child)
+				} else {
+					node.wildcard = child
+				}
+
 				return
 			}
 		}
@@ -253,35 +285,161 @@ func (node *treeNode) end(path string, data dataType, i int, offset int) (*treeN
 
 	// node: /user/|:id
 	// path: /user/|:id/profile
-	if node.parameter != nil {
-		node = node.parameter
-		offset = i
-		return node, offset, controlBegin
+	if char == parameter {
+		child := node.findParameter(path[i:])
+
+		if child != nil {
+			node = child
+			offset = i
+			return node, offset, controlBegin
+		}
 	}
 
 	node.append(path[i:], data)
 	return node, offset, controlStop
 }
 
-// each traverses the tree and calls the given function on every node.
-func (node *treeNode) each(callback func(*treeNode)) {
-	callback(node)
+// findParameter returns the existing parameter child
+// with the same definition as the given path segment.
+func (node *treeNode) findParameter(path string) *treeNode {
+	end := segmentEnd(path)
 
-	for _, child := range node.children {
-		if child == nil {
-			continue
+	if end == -1 {
+		end = len(path)
+	}
+
+	definition := path[:end]
+
+	if isPattern(definition) {
+		for _, child := range node.constrained {
+			if child.pattern != nil && child.prefix == definition[1:] {
+				return child
+			}
 		}
 
-		child.each(callback)
+		return nil
 	}
 
-	if node.parameter != nil {
-		node.parameter.each(callback)
+	_, next, _ := nextToken(definition)
+
+	if next.constraint == "" {
+		return node.parameter
+	}
+
+	for _, child := range node.constrained {
+		if child.constraint != nil && child.constraint.source == next.constraint && child.prefix == next.name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// searchParameters returns the data for the path when the segment from i to end
+// is used as a parameter value. Constrained parameters and patterns are tried
+// in the order they were registered before falling back to the unconstrained
+// parameter. If the rest of the path doesn't match below a parameter that
+// accepted the segment, its parameters are removed and the next one is tried.
+func (node *treeNode) searchParameters(path string, i uint, end uint, ctx *context) dataType {
+	value := path[i:end]
+
+	if value == "" {
+		return nil
+	}
+
+	params := ctx.paramCount
+
+	for _, child := range node.constrained {
+		if child.pattern != nil {
+			if !child.pattern.match(value, ctx) {
+				continue
+			}
+		} else {
+			if !child.constraint.match(value) {
+				continue
+			}
+
+			ctx.addParameter(child.prefix, value)
+		}
+
+		data := child.searchRest(path, end, ctx)
+
+		if data != nil {
+			return data
+		}
+
+		ctx.paramCount = params
+	}
+
+	if node.parameter == nil {
+		return nil
+	}
+
+	ctx.addParameter(node.parameter.prefix, value)
+	data := node.parameter.searchRest(path, end, ctx)
+
+	if data == nil {
+		ctx.paramCount = params
+	}
+
+	return data
+}
+
+// searchRest returns the data for the rest of the path
+// following the parameter node, starting at index end.
+func (node *treeNode) searchRest(path string, end uint, ctx *context) dataType {
+	// We reached the end.
+	if end == uint(len(path)) {
+		return node.data
+	}
+
+	// node: /:id|/posts
+	// path: /123|/posts
+	if separator < node.startIndex || separator >= node.endIndex {
+		return nil
+	}
+
+	index := node.indices[separator-node.startIndex]
+
+	if index == 0 {
+		return nil
+	}
+
+	return search(node.children[index], path, end+1, end, ctx)
+}
+
+// searchWildcard resets the parameters to the given count and returns the data
+// of the wildcard child accepting the value or nil if there is none.
+func (node *treeNode) searchWildcard(value string, params int, ctx *context) dataType {
+	if node == nil {
+		return nil
+	}
+
+	ctx.paramCount = params
+	child := node.matchWildcard(value, ctx)
+
+	if child == nil {
+		return nil
+	}
+
+	return child.data
+}
+
+// matchWildcard returns the wildcard child accepting the given value
+// and adds the parameters to the context. Wildcards with a suffix
+// are tried before the plain wildcard.
+func (node *treeNode) matchWildcard(value string, ctx *context) *treeNode {
+	for _, child := range node.wildcards {
+		if child.pattern.match(value, ctx) {
+			return child
+		}
 	}
 
 	if node.wildcard != nil {
-		node.wildcard.each(callback)
+		ctx.addParameter(node.wildcard.prefix, value)
 	}
+
+	return node.wildcard
 }
 
 // PrettyPrint prints a human-readable form of the tree to the given writer.
@@ -308,7 +466,13 @@ func (node *treeNode) prettyPrint(writer io.Writer, level int) {
 		colorFunc = color.GreenString
 	}
 
-	fmt.Fprintf(writer, "%s%s [%t]\n", prefix, colorFunc(node.prefix), node.data != nil)
+	name := node.prefix
+
+	if node.constraint != nil {
+		name += "<" + node.constraint.source + ">"
+	}
+
+	fmt.Fprintf(writer, "%s%s [%t]\n", prefix, colorFunc(name), node.data != nil)
 
 	for _, child := range node.children {
 		if child == nil {
@@ -322,6 +486,14 @@ func (node *treeNode) prettyPrint(writer io.Writer, level int) {
 		node.parameter.prettyPrint(writer, level+1)
 	}
 
+	for _, child := range node.constrained {
+		child.prettyPrint(writer, level+1)
+	}
+
+	for _, child := range node.wildcards {
+		child.prettyPrint(writer, level+1)
+	}
+
 	if node.wildcard != nil {
 		node.wildcard.prettyPrint(writer, level+1)
 	}
@@ -0,0 +1,32 @@
+module github.com/aerogo/aero/zstd
+
+go 1.22
+
+require (
+	github.com/aerogo/aero v0.0.0-00010101000000-000000000000
+	github.com/akyoto/assert v0.2.4
+	github.com/klauspost/compress v1.18.0
+)
+
+require (
+	github.com/aerogo/csp v0.1.10 // indirect
+	github.com/aerogo/http v1.1.3 // indirect
+	github.com/aerogo/session v0.1.9 // indirect
+	github.com/aerogo/session-store-memory v0.1.9 // indirect
+	github.com/akyoto/color v1.8.12 // indirect
+	github.com/akyoto/colorable v0.1.7 // indirect
+	github.com/akyoto/hash v0.5.0 // indirect
+	github.com/akyoto/stringutils v0.3.1 // indirect
+	github.com/akyoto/tty v0.1.4 // indirect
+	github.com/akyoto/uuid v1.1.3 // indirect
+	github.com/andybalholm/brotli v1.1.1 // indirect
+	github.com/fxamacker/cbor/v2 v2.7.0 // indirect
+	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
+	github.com/vmihailenco/msgpack/v5 v5.3.5 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	github.com/zeebo/xxh3 v1.0.1 // indirect
+	golang.org/x/sys v0.0.0-20211117180635-dee7805ff2e1 // indirect
+)
+
+replace github.com/aerogo/aero => ../
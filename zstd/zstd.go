@@ -0,0 +1,34 @@
+// Package zstd provides a compressor for the zstd content encoding.
+// It lives in its own module so that applications which don't need
+// zstd aren't bound to the Go version of the compression library:
+//
+//	app.RegisterCompressor("zstd", zstd.Compressor)
+package zstd
+
+import (
+	"io"
+
+	"github.com/aerogo/aero"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compressor creates a writer for the zstd encoding. The level is mapped
+// to the closest level of the encoder like in the zstd command line tool.
+// Responses are small, so the writer compresses on a single goroutine.
+func Compressor(writer io.Writer, level int) (aero.CompressionWriter, error) {
+	options := []zstd.EOption{
+		zstd.WithEncoderConcurrency(1),
+	}
+
+	if level != aero.DefaultCompressionLevel {
+		options = append(options, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	}
+
+	compressed, err := zstd.NewWriter(writer, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return compressed, nil
+}
@@ -0,0 +1,40 @@
+package zstd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/aero"
+	aerozstd "github.com/aerogo/aero/zstd"
+	"github.com/akyoto/assert"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompressor(t *testing.T) {
+	app := aero.New()
+	app.RegisterCompressor("zstd", aerozstd.Compressor)
+	body := strings.Repeat("Hello World", 100)
+
+	app.Get("/", func(ctx aero.Context) error {
+		return ctx.Text(body)
+	})
+
+	for _, acceptEncoding := range []string{"zstd", "gzip, deflate, br, zstd", "*"} {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Header().Get("Content-Encoding"), "zstd")
+
+		reader, err := zstd.NewReader(bytes.NewReader(response.Body.Bytes()))
+		assert.Nil(t, err)
+		decoded, err := ioutil.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Equal(t, string(decoded), body)
+	}
+}